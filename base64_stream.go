@@ -0,0 +1,163 @@
+package xmlrpc
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/fs"
+	"reflect"
+)
+
+var writerType = reflect.TypeOf((*io.Writer)(nil)).Elem()
+
+// ProgressFunc reports progress of streamed <base64> data: the number of bytes transferred so far,
+// and the total number of bytes when known up front, otherwise -1.
+type ProgressFunc func(transferred, total int64)
+
+// encodeBase64Reader writes data read from r as a <base64> value, encoding it while reading.
+func (e *StdEncoder) encodeBase64Reader(w io.Writer, r io.Reader) error {
+	if e.progress != nil {
+		r = &progressReader{r: r, total: lengthOf(r), progress: e.progress}
+	}
+
+	_, _ = io.WriteString(w, "<base64>")
+	enc := base64.NewEncoder(base64.StdEncoding, w)
+	if _, err := io.Copy(enc, r); err != nil {
+		return fmt.Errorf("failed to read data: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	_, _ = io.WriteString(w, "</base64>")
+
+	return nil
+}
+
+// lengthOf returns the number of bytes left in r, if it is known, otherwise -1.
+func lengthOf(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case interface{ Stat() (fs.FileInfo, error) }:
+		if info, err := r.Stat(); err == nil && info.Mode().IsRegular() {
+			return info.Size()
+		}
+	}
+
+	return -1
+}
+
+type progressReader struct {
+	r           io.Reader
+	transferred int64
+	total       int64
+	progress    ProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.transferred += int64(n)
+		r.progress(r.transferred, r.total)
+	}
+
+	return n, err
+}
+
+// writerOf returns the io.Writer a field refers to, if it is one. Such fields are sinks of decoded <base64> data, which is written
+// to them instead of being assigned. Only non-nil fields of types implementing io.Writer are sinks (e.g. an io.Writer or *os.File).
+func writerOf(field reflect.Value) (io.Writer, bool) {
+	if !field.Type().Implements(writerType) || !field.CanInterface() || isNil(field) {
+		return nil, false
+	}
+
+	return field.Interface().(io.Writer), true
+}
+
+// sinkOf returns the writer decoded <base64> data is written to instead of being assigned to the field, if there is one.
+// Sinks registered for the type of the field (see Base64Sink) take precedence over the field being a writer.
+// Sinks are only looked up for <base64> values, as registered sinks may create writers.
+func (d *StdDecoder) sinkOf(field reflect.Value, base64 bool) (io.Writer, bool) {
+	if !base64 {
+		return nil, false
+	}
+
+	if sink, ok := d.sinks[field.Type()]; ok && field.CanAddr() {
+		// Typed nil writers (e.g. a nil *os.File) are no sinks
+		if w := sink(field.Addr()); w != nil && !isNil(reflect.ValueOf(w)) {
+			return w, true
+		}
+	}
+
+	return writerOf(field)
+}
+
+// isNil reports whether v is nil, for kinds which can be.
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+// base64Sink decodes base64 text written to it in any chunks, writing decoded data to w.
+type base64Sink struct {
+	w           io.Writer
+	progress    ProgressFunc
+	pending     []byte
+	decoded     []byte
+	transferred int64
+}
+
+func (d *StdDecoder) newBase64Sink(w io.Writer) *base64Sink {
+	return &base64Sink{w: w, progress: d.progress}
+}
+
+func (s *base64Sink) Write(text []byte) (int, error) {
+	for _, c := range text {
+		switch c {
+		case ' ', '\t', '\r', '\n':
+		default:
+			s.pending = append(s.pending, c)
+		}
+	}
+
+	// Only complete quanta of 4 characters are decoded, the rest is kept for the next write
+	if err := s.flush(len(s.pending) / 4 * 4); err != nil {
+		return 0, err
+	}
+
+	return len(text), nil
+}
+
+// Close decodes the rest of the text, which must be a complete quantum.
+func (s *base64Sink) Close() error {
+	return s.flush(len(s.pending))
+}
+
+func (s *base64Sink) flush(n int) error {
+	if n == 0 {
+		return nil
+	}
+
+	if cap(s.decoded) < base64.StdEncoding.DecodedLen(n) {
+		s.decoded = make([]byte, base64.StdEncoding.DecodedLen(n))
+	}
+	decoded, err := base64.StdEncoding.Decode(s.decoded[:cap(s.decoded)], s.pending[:n])
+	if err != nil {
+		return err
+	}
+	s.pending = s.pending[:copy(s.pending, s.pending[n:])]
+
+	if _, err := s.w.Write(s.decoded[:decoded]); err != nil {
+		return err
+	}
+	s.transferred += int64(decoded)
+	if s.progress != nil {
+		s.progress(s.transferred, -1)
+	}
+
+	return nil
+}
@@ -0,0 +1,240 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdEncoder_Encode_Reader(t *testing.T) {
+	data := strings.Repeat("artifact ", 1000)
+
+	var progress []int64
+	enc := &StdEncoder{progress: func(transferred, total int64) {
+		require.Equal(t, int64(len(data)), total)
+		progress = append(progress, transferred)
+	}}
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, enc.Encode(buf, "artifacts.upload", Args{"build.log", strings.NewReader(data)}))
+	require.Equal(t, "<methodCall><methodName>artifacts.upload</methodName><params>"+
+		"<param><value><string>build.log</string></value></param>"+
+		"<param><value><base64>"+base64.StdEncoding.EncodeToString([]byte(data))+"</base64></value></param>"+
+		"</params></methodCall>", buf.String())
+
+	require.NotEmpty(t, progress)
+	require.Equal(t, int64(len(data)), progress[len(progress)-1])
+}
+
+func TestStdEncoder_Encode_Reader_Error(t *testing.T) {
+	r := io.MultiReader(strings.NewReader("abc"), &failingReader{})
+	err := (&StdEncoder{}).Encode(io.Discard, "artifacts.upload", Args{r})
+	require.ErrorContains(t, err, "cannot encode base64 value of reader: failed to read data: broken")
+}
+
+func TestStdEncoder_Encode_NilReader(t *testing.T) {
+	var r *bytes.Buffer
+	var f *os.File
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, (&StdEncoder{}).Encode(buf, "artifacts.upload", Args{r, f, io.Reader(nil)}))
+	require.Equal(t, "<methodCall><methodName>artifacts.upload</methodName><params>"+
+		"<param><value><nil/></value></param><param><value><nil/></value></param><param><value><nil/></value></param>"+
+		"</params></methodCall>", buf.String())
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("broken")
+}
+
+func TestStdDecoder_Base64Writer(t *testing.T) {
+	data := []byte(strings.Repeat("0123456789", 500))
+	body := []byte(`<methodResponse><params><param><value><struct>` +
+		`<member><name>name</name><value><string>build.log</string></value></member>` +
+		`<member><name>data</name><value><base64>` + wrap(base64.StdEncoding.EncodeToString(data), 76) + `</base64></value></member>` +
+		`</struct></value></param></params></methodResponse>`)
+
+	type artifact struct {
+		Name string
+		Data io.Writer
+	}
+
+	decoders := map[string]func(d *StdDecoder, v any) error{
+		"DecodeRaw": func(d *StdDecoder, v any) error {
+			return d.DecodeRaw(body, v)
+		},
+		"DecodeStream": func(d *StdDecoder, v any) error {
+			return d.DecodeStream(bytes.NewReader(body), v)
+		},
+	}
+
+	for name, decode := range decoders {
+		t.Run(name, func(t *testing.T) {
+			var transferred int64
+			dec := &StdDecoder{progress: func(n, total int64) {
+				require.Equal(t, int64(-1), total)
+				require.Greater(t, n, transferred)
+				transferred = n
+			}}
+
+			out := new(bytes.Buffer)
+			v := &artifact{Data: out}
+			require.NoError(t, decode(dec, v))
+			require.Equal(t, "build.log", v.Name)
+			require.Equal(t, data, out.Bytes())
+			require.Equal(t, int64(len(data)), transferred)
+
+			// Pointers to writers are sinks as well
+			out.Reset()
+			v2 := &struct {
+				Name string
+				Data *bytes.Buffer
+			}{Data: out}
+			require.NoError(t, decode(&StdDecoder{}, v2))
+			require.Equal(t, data, out.Bytes())
+		})
+	}
+}
+
+func TestStdDecoder_Base64Sink(t *testing.T) {
+	data := []byte(strings.Repeat("0123456789", 500))
+	body := []byte(`<methodResponse><params><param><value><array><data>` +
+		`<value><struct><member><name>name</name><value><string>a.log</string></value></member>` +
+		`<member><name>data</name><value><base64>` + base64.StdEncoding.EncodeToString(data) + `</base64></value></member></struct></value>` +
+		`<value><struct><member><name>name</name><value><string>b.log</string></value></member>` +
+		`<member><name>data</name><value><base64>` + base64.StdEncoding.EncodeToString(data[:10]) + `</base64></value></member></struct></value>` +
+		`</data></array></value></param></params></methodResponse>`)
+
+	// Buffers are not writers until created by the sink
+	type artifact struct {
+		Name string
+		Data *bytes.Buffer
+	}
+
+	decoders := map[string]func(d *StdDecoder, v any) error{
+		"DecodeRaw": func(d *StdDecoder, v any) error {
+			return d.DecodeRaw(body, v)
+		},
+		"DecodeStream": func(d *StdDecoder, v any) error {
+			return d.DecodeStream(bytes.NewReader(body), v)
+		},
+	}
+
+	for name, decode := range decoders {
+		t.Run(name, func(t *testing.T) {
+			var sunk int
+			dec := &StdDecoder{sinks: map[reflect.Type]func(reflect.Value) io.Writer{
+				reflect.TypeOf((*bytes.Buffer)(nil)): func(field reflect.Value) io.Writer {
+					sunk++
+					f := field.Interface().(**bytes.Buffer)
+					*f = new(bytes.Buffer)
+					return *f
+				},
+			}}
+
+			var v []artifact
+			require.NoError(t, decode(dec, &v))
+			require.Equal(t, 2, sunk)
+			require.Len(t, v, 2)
+			require.Equal(t, data, v[0].Data.Bytes())
+			require.Equal(t, data[:10], v[1].Data.Bytes())
+		})
+	}
+}
+
+func Test_base64Sink(t *testing.T) {
+	data := []byte("streamed base64 data of odd length!")
+	text := wrap(base64.StdEncoding.EncodeToString(data), 5)
+
+	for _, chunk := range []int{1, 2, 3, 7, len(text)} {
+		out := new(bytes.Buffer)
+		sink := (&StdDecoder{}).newBase64Sink(out)
+		for i := 0; i < len(text); i += chunk {
+			_, err := sink.Write([]byte(text[i:min(i+chunk, len(text))]))
+			require.NoError(t, err)
+		}
+		require.NoError(t, sink.Close())
+		require.Equal(t, data, out.Bytes(), "chunks of %d", chunk)
+	}
+
+	sink := (&StdDecoder{}).newBase64Sink(io.Discard)
+	_, err := sink.Write([]byte("YWJj!"))
+	require.NoError(t, err)
+	require.Error(t, sink.Close())
+}
+
+func TestClient_Base64Streaming(t *testing.T) {
+	srv := NewServer()
+	require.NoError(t, srv.Register("artifacts.echo", func(data []byte) ([]byte, error) {
+		return data, nil
+	}))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	var uploaded, downloaded int64
+	c, err := NewClient(ts.URL, StreamRequests(true), StreamResponses(true),
+		UploadProgress(func(n, _ int64) { uploaded = n }),
+		DownloadProgress(func(n, _ int64) { downloaded = n }),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+
+	data := bytes.Repeat([]byte{0, 1, 2, 253, 254, 255}, 100000)
+	out := new(bytes.Buffer)
+	reply := &struct{ Data io.Writer }{Data: out}
+	require.NoError(t, c.CallContext(context.Background(), "artifacts.echo", Args{bytes.NewReader(data)}, reply))
+
+	require.Equal(t, data, out.Bytes())
+	require.Equal(t, int64(len(data)), uploaded)
+	require.Equal(t, int64(len(data)), downloaded)
+}
+
+func TestClient_Option_Base64Sink(t *testing.T) {
+	srv := NewServer()
+	require.NoError(t, srv.Register("artifacts.echo", func(data []byte) ([]byte, error) {
+		return data, nil
+	}))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	dir := t.TempDir()
+	c, err := NewClient(ts.URL, Base64Sink(func(f **os.File) io.Writer {
+		*f, _ = os.Create(filepath.Join(dir, "artifact"))
+		return *f
+	}))
+	require.NoError(t, err)
+	defer c.Close()
+
+	data := bytes.Repeat([]byte{0, 1, 2, 253, 254, 255}, 1000)
+	reply := &struct{ Data *os.File }{}
+	require.NoError(t, c.CallArgs("artifacts.echo", reply, data))
+	require.NotNil(t, reply.Data)
+	require.NoError(t, reply.Data.Close())
+
+	written, err := os.ReadFile(filepath.Join(dir, "artifact"))
+	require.NoError(t, err)
+	require.Equal(t, data, written)
+}
+
+// wrap inserts line breaks into s every n characters.
+func wrap(s string, n int) string {
+	b := new(strings.Builder)
+	for i := 0; i < len(s); i += n {
+		b.WriteString(s[i:min(i+n, len(s))])
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,137 @@
+package xmlrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Bind sets every exported func field of the struct pointed to by api to a function performing the remote call.
+// This allows describing a remote API as a Go struct, which is easy to replace with a mock in tests:
+//
+//	type BugAPI struct {
+//		Version func(ctx context.Context) (VersionResult, error) `xmlrpc:"Bugzilla.version"`
+//		Get     func(ctx context.Context, args GetArgs) (GetResult, error) `xmlrpc:"Bug.get"`
+//	}
+//
+// Method name is taken from the `xmlrpc` tag, or the field name if tag is not set. Fields tagged with "-" are skipped.
+//
+// Functions may accept an optional context.Context followed by at most one argument (following the same rules as arguments of Client.Call),
+// and must return either an error, or a reply value and an error.
+// Trailing optional params of an argument struct may be tagged with `xmlrpc:",omitempty"`, so that they are left out while zero.
+// If any of the func fields has an unsupported signature, an error is returned and no fields are set.
+func (c *Client) Bind(api any) error {
+	v := reflect.ValueOf(api)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind target must be a non-nil pointer to a struct, got %T", api)
+	}
+
+	v = v.Elem()
+	bindings := make(map[int]reflect.Value)
+
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if !f.IsExported() || f.Type.Kind() != reflect.Func {
+			continue
+		}
+
+		methodName := f.Name
+		if tagValue := f.Tag.Get("xmlrpc"); tagValue == "-" {
+			continue
+		} else if tagValue != "" {
+			methodName = tagValue
+		}
+
+		if err := validateBindSignature(f.Type); err != nil {
+			return fmt.Errorf("cannot bind field '%s' to method '%s': %w", f.Name, methodName, err)
+		}
+
+		bindings[i] = reflect.MakeFunc(f.Type, c.bindFunc(f.Type, methodName))
+	}
+
+	for i, fn := range bindings {
+		v.Field(i).Set(fn)
+	}
+
+	return nil
+}
+
+// bindFunc returns an implementation of a func with provided type, that performs a remote call.
+func (c *Client) bindFunc(fnType reflect.Type, methodName string) func(in []reflect.Value) []reflect.Value {
+	hasContext := fnType.NumIn() > 0 && fnType.In(0) == contextType
+	hasReply := fnType.NumOut() == 2 //nolint:mnd // (reply, error)
+
+	return func(in []reflect.Value) []reflect.Value {
+		ctx := context.Background()
+		if hasContext {
+			if !in[0].IsNil() {
+				ctx = in[0].Interface().(context.Context)
+			}
+			in = in[1:]
+		}
+
+		var args any
+		if len(in) == 1 {
+			args = in[0].Interface()
+		}
+
+		var reply reflect.Value
+		var replyPtr any
+		if hasReply {
+			reply = reflect.New(fnType.Out(0))
+			replyPtr = reply.Interface()
+		}
+
+		err := c.CallContext(ctx, methodName, args, replyPtr)
+
+		errValue := reflect.Zero(errorType)
+		if err != nil {
+			errValue = reflect.ValueOf(&err).Elem()
+		}
+
+		if !hasReply {
+			return []reflect.Value{errValue}
+		}
+
+		if err != nil {
+			return []reflect.Value{reflect.Zero(fnType.Out(0)), errValue}
+		}
+
+		return []reflect.Value{reply.Elem(), errValue}
+	}
+}
+
+func validateBindSignature(fnType reflect.Type) error {
+	if fnType.IsVariadic() {
+		return errors.New("variadic functions are not supported, use Args instead")
+	}
+
+	numArgs := fnType.NumIn()
+	if numArgs > 0 && fnType.In(0) == contextType {
+		numArgs--
+	}
+	if numArgs > 1 {
+		return fmt.Errorf("function must accept at most one argument (besides context.Context), got %d", numArgs)
+	}
+
+	switch fnType.NumOut() {
+	case 1:
+		if fnType.Out(0) != errorType {
+			return errors.New("single return value must be an error")
+		}
+	case 2: //nolint:mnd // (reply, error)
+		if fnType.Out(1) != errorType {
+			return errors.New("second return value must be an error")
+		}
+	default:
+		return fmt.Errorf("function must return (reply, error) or error, got %d return values", fnType.NumOut())
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package xmlrpc
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Bind(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m := &struct {
+			Name   string           `xml:"methodName"`
+			Params []*ResponseParam `xml:"params>param"`
+		}{}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err, "test server: read body")
+		require.NoError(t, xml.Unmarshal(body, m), "test server: unmarshal body")
+
+		switch m.Name {
+		case "Bugzilla.version":
+			require.Empty(t, m.Params)
+			_, _ = fmt.Fprint(w, string(loadTestFile(t, "response_bugzilla_version.xml")))
+		case "Bug.get":
+			require.Len(t, m.Params, 1)
+			require.NotNil(t, m.Params[0].Value.Array)
+			_, _ = fmt.Fprint(w, string(loadTestFile(t, "response_bugs.xml")))
+		case "Bug.update":
+			_, _ = fmt.Fprint(w, string(loadTestFile(t, "response_fault.xml")))
+		default:
+			require.Failf(t, "unexpected method", "method: %s", m.Name)
+		}
+	}))
+	defer ts.Close()
+
+	type GetArgs struct {
+		Ids []int
+	}
+	api := &struct {
+		Version    func(ctx context.Context) (map[string]string, error)   `xmlrpc:"Bugzilla.version"`
+		Get        func(ctx context.Context, args GetArgs) (Value, error) `xmlrpc:"Bug.get"`
+		Update     func(args map[string]any) error                        `xmlrpc:"Bug.update"`
+		Skipped    func(int, int, int)                                    `xmlrpc:"-"`
+		NotFunc    string
+		unexported func()
+	}{}
+
+	c, err := NewClient(ts.URL)
+	require.NoError(t, err)
+	defer c.Close()
+
+	require.NoError(t, c.Bind(api))
+	require.Nil(t, api.Skipped)
+	require.Nil(t, api.unexported)
+
+	version, err := api.Version(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"version": "20220802.1"}, version)
+
+	result, err := api.Get(context.Background(), GetArgs{Ids: []int{35, 36}})
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Member("bugs").Len())
+
+	err = api.Update(map[string]any{"id": 35})
+	fT := &Fault{}
+	require.True(t, errors.As(err, &fT))
+	require.Equal(t, 4, fT.Code)
+}
+
+func TestClient_Bind_Errors(t *testing.T) {
+	c, err := NewClient("http://localhost")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		api  any
+		err  string
+	}{
+		{
+			name: "not a pointer",
+			api:  struct{}{},
+			err:  "bind target must be a non-nil pointer to a struct, got struct {}",
+		},
+		{
+			name: "pointer to non-struct",
+			api:  new(int),
+			err:  "bind target must be a non-nil pointer to a struct, got *int",
+		},
+		{
+			name: "too many arguments",
+			api: &struct {
+				Get func(ctx context.Context, a, b int) error
+			}{},
+			err: "cannot bind field 'Get' to method 'Get': function must accept at most one argument (besides context.Context), got 2",
+		},
+		{
+			name: "variadic",
+			api: &struct {
+				Get func(args ...any) error `xmlrpc:"d.get"`
+			}{},
+			err: "cannot bind field 'Get' to method 'd.get': variadic functions are not supported, use Args instead",
+		},
+		{
+			name: "no error returned",
+			api: &struct {
+				Get func() string
+			}{},
+			err: "cannot bind field 'Get' to method 'Get': single return value must be an error",
+		},
+		{
+			name: "error not last",
+			api: &struct {
+				Get func() (error, string)
+			}{},
+			err: "cannot bind field 'Get' to method 'Get': second return value must be an error",
+		},
+		{
+			name: "no return values",
+			api: &struct {
+				Get func()
+			}{},
+			err: "cannot bind field 'Get' to method 'Get': function must return (reply, error) or error, got 0 return values",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.Bind(tt.api)
+			require.Error(t, err)
+			require.True(t, strings.HasPrefix(err.Error(), tt.err), err.Error())
+		})
+	}
+
+	// No fields are set if any of them is invalid
+	api := &struct {
+		Valid   func() error
+		Invalid func()
+	}{}
+	require.Error(t, c.Bind(api))
+	require.Nil(t, api.Valid)
+}
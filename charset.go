@@ -0,0 +1,122 @@
+package xmlrpc
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+// requestCharset resolves a charset label (e.g. "ISO-8859-1" or "latin1") into its encoding and preferred MIME name,
+// which requests declare. UTF-8 resolves into a nil encoding, as requests need no transcoding.
+func requestCharset(label string) (encoding.Encoding, string, error) {
+	enc, err := ianaindex.MIME.Encoding(label)
+	if err != nil || enc == nil {
+		return nil, "", fmt.Errorf("unsupported charset '%s'", label)
+	}
+
+	name, err := ianaindex.MIME.Name(enc)
+	if err != nil {
+		return nil, "", fmt.Errorf("unsupported charset '%s'", label)
+	}
+	if name == "UTF-8" {
+		return nil, name, nil
+	}
+
+	return enc, name, nil
+}
+
+// contentType returns Content-Type of requests written by Encode.
+func (e *StdEncoder) contentType() string {
+	if e.charset == "" {
+		return "text/xml"
+	}
+
+	if _, name, err := requestCharset(e.charset); err == nil {
+		return "text/xml; charset=" + name
+	}
+
+	return "text/xml"
+}
+
+// charsetWriter transcodes UTF-8 text written to it into a charset, failing on characters the charset cannot represent.
+type charsetWriter struct {
+	w       io.Writer
+	enc     *encoding.Encoder
+	name    string
+	pending []byte
+	offset  int
+	// err is kept, as encoding ignores errors of some writes
+	err error
+}
+
+func newCharsetWriter(w io.Writer, enc encoding.Encoding, name string) *charsetWriter {
+	return &charsetWriter{w: w, enc: enc.NewEncoder(), name: name}
+}
+
+func (w *charsetWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	text := p
+	if len(w.pending) > 0 {
+		text = append(w.pending, p...)
+		w.pending = nil
+	}
+
+	// An incomplete character at the end is kept for the next write
+	end := len(text)
+	for start := end - 1; start >= max(end-utf8.UTFMax+1, 0); start-- {
+		if utf8.RuneStart(text[start]) {
+			if !utf8.FullRune(text[start:end]) {
+				w.pending = append(w.pending, text[start:end]...)
+				end = start
+			}
+			break
+		}
+	}
+
+	encoded, err := w.enc.Bytes(text[:end])
+	if err != nil {
+		w.err = w.unrepresentable(text[:end])
+		return 0, w.err
+	}
+	w.offset += end
+
+	if _, w.err = w.w.Write(encoded); w.err != nil {
+		return 0, w.err
+	}
+
+	return len(p), nil
+}
+
+// unrepresentable returns an error describing the first character of text which cannot be represented in the charset.
+func (w *charsetWriter) unrepresentable(text []byte) error {
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRune(text[i:])
+		if _, err := w.enc.Bytes(text[i : i+size]); err != nil {
+			if r == utf8.RuneError && size == 1 {
+				return fmt.Errorf("invalid UTF-8 at offset %d", w.offset+i)
+			}
+			return fmt.Errorf("character %U at offset %d cannot be represented in %s", r, w.offset+i, w.name)
+		}
+		i += size
+	}
+
+	return fmt.Errorf("text at offset %d cannot be represented in %s", w.offset, w.name)
+}
+
+// Close fails if any write failed, or the text written ends with an incomplete character.
+func (w *charsetWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(w.pending) > 0 {
+		return fmt.Errorf("invalid UTF-8 at offset %d", w.offset)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdEncoder_Encode_Charset(t *testing.T) {
+	tests := map[string]struct {
+		enc    StdEncoder
+		args   any
+		expect string
+		err    string
+	}{
+		"declaration": {
+			enc:    StdEncoder{xmlDeclaration: true},
+			args:   Args{"Zoë"},
+			expect: `<?xml version="1.0"?><methodCall><methodName>m</methodName><params><param><value><string>Zoë</string></value></param></params></methodCall>`,
+		},
+		"UTF-8": {
+			enc:    StdEncoder{charset: "utf-8"},
+			args:   Args{"Zoë"},
+			expect: `<?xml version="1.0" encoding="UTF-8"?><methodCall><methodName>m</methodName><params><param><value><string>Zoë</string></value></param></params></methodCall>`,
+		},
+		"ISO-8859-1": {
+			enc:    StdEncoder{charset: "latin1"},
+			args:   Args{"Zoë & Chloé"},
+			expect: "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><methodCall><methodName>m</methodName><params><param><value><string>Zo\xeb &amp; Chlo\xe9</string></value></param></params></methodCall>",
+		},
+		"windows-1252": {
+			enc:    StdEncoder{charset: "windows-1252"},
+			args:   Args{map[string]any{"naïve": "5 €"}},
+			expect: "<?xml version=\"1.0\" encoding=\"windows-1252\"?><methodCall><methodName>m</methodName><params><param><value><struct><member><name>na\xefve</name><value><string>5 \x80</string></value></member></struct></value></param></params></methodCall>",
+		},
+		"unrepresentable character": {
+			enc:  StdEncoder{charset: "ISO-8859-1"},
+			args: Args{"5 €"},
+			err:  "character U+20AC at offset 113 cannot be represented in ISO-8859-1",
+		},
+		"unrepresentable member name": {
+			enc:  StdEncoder{charset: "ISO-8859-1"},
+			args: Args{map[string]any{"€": 5}},
+			err:  "character U+20AC at offset",
+		},
+		"unsupported charset": {
+			enc:  StdEncoder{charset: "x-legacy"},
+			args: Args{"a"},
+			err:  "unsupported charset 'x-legacy'",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := tt.enc.Encode(buf, "m", tt.args)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, buf.String())
+		})
+	}
+}
+
+func Test_charsetWriter(t *testing.T) {
+	enc, name, err := requestCharset("ISO-8859-1")
+	require.NoError(t, err)
+
+	// Characters split across writes are transcoded once complete
+	out := new(bytes.Buffer)
+	w := newCharsetWriter(out, enc, name)
+	for _, b := range []byte("café ü") {
+		_, err := w.Write([]byte{b})
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	require.Equal(t, "caf\xe9 \xfc", out.String())
+
+	w = newCharsetWriter(io.Discard, enc, name)
+	_, err = w.Write([]byte("caf\xc3"))
+	require.NoError(t, err)
+	require.EqualError(t, w.Close(), "invalid UTF-8 at offset 3")
+
+	// Errors are kept
+	w = newCharsetWriter(io.Discard, enc, name)
+	_, err = w.Write([]byte("ab€"))
+	require.EqualError(t, err, "character U+20AC at offset 2 cannot be represented in ISO-8859-1")
+	_, err = w.Write([]byte("c"))
+	require.Error(t, err)
+	require.Error(t, w.Close())
+}
+
+func TestClient_Option_RequestCharset(t *testing.T) {
+	var contentType string
+	var body []byte
+	srv := NewServer()
+	require.NoError(t, srv.Register("users.greet", func(name string) (string, error) {
+		return "Hello, " + name, nil
+	}))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		srv.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	for _, stream := range []bool{false, true} {
+		c, err := NewClient(ts.URL, RequestCharset("windows-1252"), StreamRequests(stream))
+		require.NoError(t, err)
+
+		var greeting string
+		require.NoError(t, c.CallArgs("users.greet", &greeting, "Zoë"))
+		require.Equal(t, "Hello, Zoë", greeting)
+		require.Equal(t, "text/xml; charset=windows-1252", contentType)
+		require.Contains(t, string(body), "<string>Zo\xeb</string>")
+
+		err = c.CallArgs("users.greet", &greeting, "Zoë 😀")
+		require.ErrorContains(t, err, "character U+1F600 at offset")
+		require.NoError(t, c.Close())
+	}
+
+	c, err := NewClient(ts.URL, XMLDeclaration(true))
+	require.NoError(t, err)
+	defer c.Close()
+
+	var greeting string
+	require.NoError(t, c.CallArgs("users.greet", &greeting, "Zoë"))
+	require.Equal(t, "text/xml", contentType)
+	require.Contains(t, string(body), `<?xml version="1.0"?><methodCall>`)
+}
@@ -1,6 +1,7 @@
 package xmlrpc
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/rpc"
@@ -14,6 +15,7 @@ type Client struct {
 }
 
 // NewClient creates a Client with http.DefaultClient.
+// Endpoints with unix://, scgi:// and scgi+unix:// schemes are reached using Transport instead (see Transport for details).
 // If provided endpoint is not valid, an error is returned.
 func NewClient(endpoint string, opts ...Option) (*Client, error) {
 	// Parse Endpoint URL
@@ -22,7 +24,13 @@ func NewClient(endpoint string, opts ...Option) (*Client, error) {
 		return nil, fmt.Errorf("invalid endpoint url: %w", err)
 	}
 
-	codec := NewCodec(endpointURL, http.DefaultClient)
+	httpClient := http.DefaultClient
+	switch endpointURL.Scheme {
+	case SchemeUnix, SchemeSCGI, SchemeSCGIUnix:
+		httpClient = &http.Client{Transport: &Transport{}}
+	}
+
+	codec := NewCodec(endpointURL, httpClient)
 
 	c := &Client{
 		codec:  codec,
@@ -37,6 +45,39 @@ func NewClient(endpoint string, opts ...Option) (*Client, error) {
 	return c, nil
 }
 
+// CallArgs invokes the named function with positional arguments, waits for it to complete, and returns its error status.
+// Each of the args is encoded as a separate param, which removes the need of a wrapper struct for arguments.
+func (c *Client) CallArgs(serviceMethod string, reply any, args ...any) error {
+	return c.Call(serviceMethod, Args(args), reply)
+}
+
+// CallContext invokes the named function, waits for it to complete or for the context to be done, and returns its error status.
+// Unlike Call, the original error is returned (e.g. *Fault when the server responds with a fault) instead of rpc.ServerError.
+func (c *Client) CallContext(ctx context.Context, serviceMethod string, args any, reply any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cc := &callContext{ctx: ctx, args: args, reply: reply}
+	err := c.Client.Call(serviceMethod, cc, reply)
+	if err != nil && cc.err != nil {
+		return cc.err
+	}
+
+	return err
+}
+
+// Call invokes the named function, waits for it to complete, and returns its error status.
+// When responses are streamed (see StreamResponses), the reply is decoded while reading the response, as with CallContext.
+// Errors of decoding are then returned as rpc.ServerError, instead of shutting down the client.
+func (c *Client) Call(serviceMethod string, args any, reply any) error {
+	if _, ok := args.(*callContext); !ok && c.codec.streaming {
+		args = &callContext{ctx: context.Background(), args: args, reply: reply}
+	}
+
+	return c.Client.Call(serviceMethod, args, reply)
+}
+
 // NewCustomClient allows customization of http.Client used to make RPC calls.
 // If provided endpoint is not valid, an error is returned.
 //
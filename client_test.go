@@ -68,6 +68,36 @@ func TestClient_Call(t *testing.T) {
 	require.Equal(t, 12345, resp.Index)
 }
 
+func TestClient_CallArgs(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m := &struct {
+			Name   string           `xml:"methodName"`
+			Params []*ResponseParam `xml:"params>param"`
+		}{}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err, "test server: read body")
+
+		err = xml.Unmarshal(body, m)
+		require.NoError(t, err, "test server: unmarshal body")
+
+		require.Equal(t, "d.name", m.Name)
+		require.Equal(t, 2, len(m.Params))
+		require.Equal(t, "5A8D3A43", *m.Params[0].Value.String)
+		require.Equal(t, "1", *m.Params[1].Value.Int)
+
+		_, _ = fmt.Fprintln(w, string(loadTestFile(t, "response_array.xml")))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	var out []int
+	err = c.CallArgs("d.name", &out, "5A8D3A43", 1)
+	require.NoError(t, err)
+	require.Equal(t, []int{10, 11, 12}, out)
+}
+
 func TestClient_Github_86(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		m := &struct {
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"alexejk.io/go-xmlrpc/codegen"
+)
+
+func runIDL(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("idl", flag.ContinueOnError)
+	schemaPath := fs.String("schema", "", "JSON schema describing the service")
+	pkg := fs.String("pkg", "api", "name of generated package")
+	out := fs.String("o", "", "output file (default is standard output)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *schemaPath == "" {
+		return errors.New("-schema must be specified")
+	}
+
+	f, err := os.Open(*schemaPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	schema, err := codegen.ReadSchema(f)
+	if err != nil {
+		return err
+	}
+
+	src, err := codegen.GenerateService(schema, codegen.Config{Package: *pkg})
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*out, stdout, src)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"alexejk.io/go-xmlrpc/codegen"
+)
+
+func runInfer(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("infer", flag.ContinueOnError)
+	pkg := fs.String("pkg", "api", "name of generated package")
+	typeName := fs.String("type", "Response", "name of generated root type")
+	out := fs.String("o", "", "output file (default is standard output)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return errors.New("at least one sample response file must be specified")
+	}
+
+	samples := make([][]byte, 0, fs.NArg())
+	for _, path := range fs.Args() {
+		sample, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		samples = append(samples, sample)
+	}
+
+	src, err := codegen.InferTypes(samples, codegen.Config{
+		Package:  *pkg,
+		TypeName: *typeName,
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*out, stdout, src)
+}
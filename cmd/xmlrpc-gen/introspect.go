@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"alexejk.io/go-xmlrpc"
+	"alexejk.io/go-xmlrpc/codegen"
+)
+
+func runIntrospect(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("introspect", flag.ContinueOnError)
+	endpoint := fs.String("url", "", "XML-RPC endpoint to introspect")
+	dump := fs.String("dump", "", "read introspection results from a JSON dump instead of the endpoint")
+	save := fs.String("save", "", "save introspection results as a JSON dump")
+	pkg := fs.String("pkg", "api", "name of generated package")
+	typeName := fs.String("type", "Client", "name of generated client type")
+	out := fs.String("o", "", "output file (default is standard output)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	intro, err := loadIntrospection(*endpoint, *dump)
+	if err != nil {
+		return err
+	}
+
+	if *save != "" {
+		if err := saveIntrospection(*save, intro); err != nil {
+			return err
+		}
+	}
+
+	src, err := codegen.GenerateClient(intro, codegen.Config{
+		Package:  *pkg,
+		TypeName: *typeName,
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*out, stdout, src)
+}
+
+func loadIntrospection(endpoint, dump string) (*codegen.Introspection, error) {
+	switch {
+	case dump != "":
+		f, err := os.Open(dump)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		return codegen.ReadIntrospection(f)
+
+	case endpoint != "":
+		client, err := xmlrpc.NewClient(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		defer client.Close()
+
+		return codegen.Introspect(context.Background(), client)
+
+	default:
+		return nil, errors.New("either -url or -dump must be specified")
+	}
+}
+
+func saveIntrospection(path string, intro *codegen.Introspection) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = intro.WriteTo(f)
+	return err
+}
@@ -0,0 +1,77 @@
+// Command xmlrpc-gen generates typed Go code for XML-RPC APIs.
+//
+// Usage:
+//
+//	xmlrpc-gen <command> [flags]
+//
+// Commands:
+//
+//	introspect  generate a typed client out of server introspection (system.listMethods, system.methodSignature, system.methodHelp)
+//	idl         generate typed client and server code out of a JSON schema of the service
+//	infer       generate Go types out of sample <methodResponse> documents
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// command is a single subcommand of the tool.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, stdout io.Writer) error
+}
+
+var commands = []command{
+	{name: "introspect", usage: "generate a typed client out of server introspection", run: runIntrospect},
+	{name: "idl", usage: "generate typed client and server code out of a JSON schema", run: runIDL},
+	{name: "infer", usage: "generate Go types out of sample responses (files as arguments)", run: runInfer},
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "xmlrpc-gen: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		printUsage(os.Stderr)
+		return errors.New("no command specified")
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:], stdout)
+		}
+	}
+
+	printUsage(os.Stderr)
+	return fmt.Errorf("unknown command '%s'", args[0])
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: xmlrpc-gen <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", c.name, c.usage)
+	}
+}
+
+// writeOutput writes generated source into a file, or provided writer if no file is set.
+func writeOutput(path string, stdout io.Writer, src []byte) error {
+	if path == "" {
+		_, err := stdout.Write(src)
+		return err
+	}
+
+	return os.WriteFile(path, src, 0o644) //nolint:gosec,mnd // Generated source is meant to be readable
+}
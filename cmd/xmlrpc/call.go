@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"alexejk.io/go-xmlrpc"
+)
+
+const (
+	formatJSON = "json"
+	formatXML  = "xml"
+)
+
+// connection contains flags shared by commands connecting to an endpoint.
+type connection struct {
+	headers stringList
+	user    string
+	timeout time.Duration
+	dump    bool
+}
+
+func (c *connection) register(fs *flag.FlagSet) {
+	fs.Var(&c.headers, "H", "additional request header as 'Name: value' (may be repeated)")
+	fs.StringVar(&c.user, "u", "", "basic authentication credentials as 'user:password'")
+	fs.DurationVar(&c.timeout, "timeout", 30*time.Second, "timeout of a call") //nolint:mnd // default timeout
+	fs.BoolVar(&c.dump, "dump", false, "print requests and responses to standard error")
+}
+
+// client creates a client for the endpoint, returning the transport that records the last response body.
+func (c *connection) client(endpoint string, stderr io.Writer) (*xmlrpc.Client, *recordingTransport, error) {
+	headers := make(map[string]string)
+	for _, h := range c.headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok {
+			return nil, nil, &usageError{msg: fmt.Sprintf("invalid header '%s', expected 'Name: value'", h)}
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	if c.user != "" {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(c.user))
+	}
+
+	transport := &recordingTransport{next: &xmlrpc.Transport{}}
+	if c.dump {
+		transport.dump = stderr
+	}
+
+	client, err := xmlrpc.NewClient(endpoint,
+		xmlrpc.HttpClient(&http.Client{Transport: transport}),
+		xmlrpc.Headers(headers),
+		xmlrpc.SortMapKeys(true),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, transport, nil
+}
+
+func runCall(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("call", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: xmlrpc call [flags] URL method [args...]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Arguments are JSON values, or typed literals: i4:5, double:1.5, bool:true, str:text,")
+		fmt.Fprintln(stderr, "b64:<base64>, b64:@file, dt:2024-01-01T00:00:00, json:<json>, nil")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+
+	conn := &connection{}
+	conn.register(fs)
+	argsJSON := fs.String("args", "", "arguments as a JSON array (instead of positional arguments)")
+	format := fs.String("format", formatJSON, "output format: json or xml")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) < 2 { //nolint:mnd // URL and method
+		fs.Usage()
+		return &usageError{msg: "URL and method must be specified"}
+	}
+	if *argsJSON != "" && len(positional) > 2 { //nolint:mnd // URL and method
+		return &usageError{msg: "arguments must be provided either with --args or as positional arguments"}
+	}
+	if *format != formatJSON && *format != formatXML {
+		return &usageError{msg: fmt.Sprintf("unknown output format '%s'", *format)}
+	}
+
+	params, err := parseParams(*argsJSON, positional[2:])
+	if err != nil {
+		return &usageError{msg: err.Error()}
+	}
+
+	client, transport, err := conn.client(positional[0], stderr)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), conn.timeout)
+	defer cancel()
+
+	var result []xmlrpc.Value
+	callErr := client.CallContext(ctx, positional[1], xmlrpc.Args(params), &result)
+
+	fault := &xmlrpc.Fault{}
+	isFault := errors.As(callErr, &fault)
+	if callErr != nil && !isFault {
+		return callErr
+	}
+
+	if err := writeResult(stdout, *format, transport.lastBody, result, fault, isFault); err != nil {
+		return err
+	}
+
+	if isFault {
+		fmt.Fprintf(stderr, "xmlrpc: fault %d: %s\n", fault.Code, fault.String)
+		return &exitCodeError{code: exitFault}
+	}
+
+	return nil
+}
+
+// writeResult prints the response as raw XML, or JSON of its params (a single value, if the response has one param).
+// Faults are printed as JSON objects with faultCode and faultString.
+func writeResult(w io.Writer, format string, body []byte, result []xmlrpc.Value, fault *xmlrpc.Fault, isFault bool) error {
+	if format == formatXML {
+		_, err := w.Write(body)
+		if len(body) > 0 && body[len(body)-1] != '\n' {
+			_, _ = io.WriteString(w, "\n")
+		}
+		return err
+	}
+
+	var v any
+	switch {
+	case isFault:
+		v = map[string]any{"faultCode": fault.Code, "faultString": fault.String}
+	case len(result) == 1:
+		v = jsonValue(result[0])
+	default:
+		params := make([]any, len(result))
+		for i, r := range result {
+			params[i] = jsonValue(r)
+		}
+		v = params
+	}
+
+	out, err := marshalJSON(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(out)
+	return err
+}
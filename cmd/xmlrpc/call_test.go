@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"alexejk.io/go-xmlrpc"
+)
+
+func testServer(t *testing.T) *httptest.Server {
+	s := xmlrpc.NewServer()
+	require.NoError(t, s.Register("sample.add", func(args struct{ A, B int }) (int, error) {
+		return args.A + args.B, nil
+	}))
+	require.NoError(t, s.Register("sample.echo", func(args []xmlrpc.Value) ([]xmlrpc.Value, error) {
+		return args, nil
+	}))
+	require.NoError(t, s.Register("sample.user", func(ctx context.Context) (any, error) {
+		return struct {
+			Login   string    `xmlrpc:"login"`
+			Created time.Time `xmlrpc:"created"`
+			Groups  []string  `xmlrpc:"groups"`
+		}{Login: "alice", Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Groups: []string{"admin"}}, nil
+	}))
+	require.NoError(t, s.Register("sample.fail", func() error {
+		return &xmlrpc.Fault{Code: 42, String: "failed on purpose"}
+	}))
+	require.NoError(t, s.Register("system.listMethods", func() ([]string, error) {
+		return s.Methods(), nil
+	}))
+	require.NoError(t, s.Register("system.methodSignature", func(name string) (any, error) {
+		if name == "sample.add" {
+			return [][]string{{"int", "int", "int"}}, nil
+		}
+		return "undef", nil
+	}))
+	require.NoError(t, s.Register("system.methodHelp", func(name string) (string, error) {
+		return "Help of " + name + ".", nil
+	}))
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Test") != "" {
+			w.Header().Set("X-Test", r.Header.Get("X-Test"))
+		}
+		if user, pass, ok := r.BasicAuth(); ok && (user != "alice" || pass != "secret") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		s.ServeHTTP(w, r)
+	}))
+}
+
+func TestRunCall(t *testing.T) {
+	ts := testServer(t)
+	defer ts.Close()
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{
+			name:   "positional arguments",
+			args:   []string{"call", ts.URL, "sample.add", "2", "i4:3"},
+			stdout: "5\n",
+		},
+		{
+			name:   "json arguments after positional",
+			args:   []string{"call", ts.URL, "sample.add", "--args", "[2, 3]"},
+			stdout: "5\n",
+		},
+		{
+			name:   "negative numbers",
+			args:   []string{"call", ts.URL, "sample.add", "-5", "-format", "json", "-1"},
+			stdout: "-6\n",
+		},
+		{
+			name:   "typed arguments",
+			args:   []string{"call", "--args", `["text", 1.5, "bool:1", "b64:aGk=", "dt:2024-01-01T00:00:00", null]`, ts.URL, "sample.echo"},
+			stdout: "[\n  \"text\",\n  1.5,\n  true,\n  \"aGk=\",\n  \"2024-01-01T00:00:00Z\",\n  null\n]\n",
+		},
+		{
+			name:   "struct keeps member order",
+			args:   []string{"call", ts.URL, "sample.user"},
+			stdout: "{\n  \"login\": \"alice\",\n  \"created\": \"2024-01-02T03:04:05Z\",\n  \"groups\": [\n    \"admin\"\n  ]\n}\n",
+		},
+		{
+			name:   "raw xml",
+			args:   []string{"call", "-format", "xml", ts.URL, "sample.add", "2", "3"},
+			stdout: "<methodResponse><params><param><value><int>5</int></value></param></params></methodResponse>\n",
+		},
+		{
+			name:   "fault",
+			args:   []string{"call", ts.URL, "sample.fail"},
+			code:   exitFault,
+			stdout: "{\n  \"faultCode\": 42,\n  \"faultString\": \"failed on purpose\"\n}\n",
+			stderr: "xmlrpc: fault 42: failed on purpose\n",
+		},
+		{
+			name:   "basic auth",
+			args:   []string{"call", "-u", "alice:wrong", ts.URL, "sample.add", "2", "3"},
+			code:   exitError,
+			stderr: "xmlrpc: bad response code: 401\n",
+		},
+		{
+			name:   "missing method",
+			args:   []string{"call", ts.URL},
+			code:   exitUsage,
+			stderr: "xmlrpc: URL and method must be specified\n",
+		},
+		{
+			name:   "both kinds of arguments",
+			args:   []string{"call", "--args", "[1]", ts.URL, "sample.add", "2"},
+			code:   exitUsage,
+			stderr: "xmlrpc: arguments must be provided either with --args or as positional arguments\n",
+		},
+		{
+			name:   "invalid literal",
+			args:   []string{"call", ts.URL, "sample.add", "i4:two"},
+			code:   exitUsage,
+			stderr: "xmlrpc: invalid argument 1: invalid i4 literal 'two': strconv.Atoi: parsing \"two\": invalid syntax\n",
+		},
+		{
+			name:   "unknown command",
+			args:   []string{"fetch"},
+			code:   exitUsage,
+			stderr: "Usage: xmlrpc <command> [flags]\n\nCommands:\n  call   call a method: xmlrpc call [flags] URL method [args...]\n  shell  start an interactive shell: xmlrpc shell [flags] URL\n  fmt    print documents in canonical form: xmlrpc fmt [-w] [file...]\n  lint   report specification violations of documents: xmlrpc lint [file...]\nxmlrpc: unknown command 'fetch'\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+			code := exitCode(run(tt.args, nil, stdout, stderr), stderr)
+
+			require.Equal(t, tt.code, code, stderr.String())
+			require.Equal(t, tt.stdout, stdout.String())
+			if tt.stderr != "" {
+				require.True(t, strings.HasSuffix(stderr.String(), tt.stderr), stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunCall_Dump(t *testing.T) {
+	ts := testServer(t)
+	defer ts.Close()
+
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	err := run([]string{"call", "--dump", "-H", "X-Test: yes", "-u", "alice:secret", ts.URL, "sample.add", "2", "3"}, nil, stdout, stderr)
+	require.NoError(t, err)
+	require.Equal(t, "5\n", stdout.String())
+
+	dump := stderr.String()
+	require.Contains(t, dump, "> POST "+ts.URL+"\n")
+	require.Contains(t, dump, "> Authorization: <redacted>\n")
+	require.Contains(t, dump, "> X-Test: yes\n")
+	require.Contains(t, dump, "> <methodCall><methodName>sample.add</methodName>")
+	require.Contains(t, dump, "< 200 OK\n")
+	require.Contains(t, dump, "< X-Test: yes\n")
+	require.Contains(t, dump, "< <methodResponse><params><param><value><int>5</int></value></param></params></methodResponse>\n")
+}
+
+func Test_parseInterspersed(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		positional []string
+		n          int
+		verbose    bool
+	}{
+		{
+			name:       "flags between arguments",
+			args:       []string{"a", "-n", "2", "b", "-v", "c"},
+			positional: []string{"a", "b", "c"},
+			n:          2,
+			verbose:    true,
+		},
+		{
+			name:       "negative numbers",
+			args:       []string{"-5", "a", "-1.5", "-v", "-2e3"},
+			positional: []string{"-5", "a", "-1.5", "-2e3"},
+			verbose:    true,
+		},
+		{
+			name:       "negative number as flag value",
+			args:       []string{"a", "-n", "-5", "-3"},
+			positional: []string{"a", "-3"},
+			n:          -5,
+		},
+		{
+			name:       "negative number after boolean flag",
+			args:       []string{"-v", "-3"},
+			positional: []string{"-3"},
+			verbose:    true,
+		},
+		{
+			name:       "terminator",
+			args:       []string{"a", "--", "-v", "-n"},
+			positional: []string{"a", "-v", "-n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			n := fs.Int("n", 0, "")
+			verbose := fs.Bool("v", false, "")
+
+			positional, err := parseInterspersed(fs, tt.args)
+			require.NoError(t, err)
+			require.Equal(t, tt.positional, positional)
+			require.Equal(t, tt.n, *n)
+			require.Equal(t, tt.verbose, *verbose)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+)
+
+// recordingTransport keeps the body of the last response, and optionally prints requests and responses.
+type recordingTransport struct {
+	next http.RoundTripper
+	dump io.Writer
+
+	lastBody []byte
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		if t.dump != nil {
+			fmt.Fprintf(t.dump, "> %s %s\n", req.Method, req.URL.Redacted())
+			dumpHeaders(t.dump, "> ", req.Header)
+			dumpBody(t.dump, "> ", body)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	t.lastBody = body
+
+	if t.dump != nil {
+		fmt.Fprintf(t.dump, "< %s\n", resp.Status)
+		dumpHeaders(t.dump, "< ", resp.Header)
+		dumpBody(t.dump, "< ", body)
+	}
+
+	return resp, nil
+}
+
+func (t *recordingTransport) CloseIdleConnections() {
+	if ci, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		ci.CloseIdleConnections()
+	}
+}
+
+func dumpHeaders(w io.Writer, prefix string, h http.Header) {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := h.Get(name)
+		if name == "Authorization" {
+			value = "<redacted>"
+		}
+		fmt.Fprintf(w, "%s%s: %s\n", prefix, name, value)
+	}
+	fmt.Fprintln(w, prefix)
+}
+
+func dumpBody(w io.Writer, prefix string, body []byte) {
+	for _, line := range bytes.Split(bytes.TrimRight(body, "\n"), []byte("\n")) {
+		fmt.Fprintf(w, "%s%s\n", prefix, line)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"alexejk.io/go-xmlrpc"
+)
+
+// stdinName is the name of standard input in messages.
+const stdinName = "<stdin>"
+
+// document is an input file of fmt and lint commands.
+type document struct {
+	name string
+	body []byte
+}
+
+// readDocuments reads the named files, or standard input if no files are named.
+func readDocuments(names []string, stdin io.Reader) ([]document, error) {
+	if len(names) == 0 {
+		if stdin == nil {
+			stdin = bytes.NewReader(nil)
+		}
+		body, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read standard input: %w", err)
+		}
+		return []document{{name: stdinName, body: body}}, nil
+	}
+
+	docs := make([]document, 0, len(names))
+	for _, name := range names {
+		body, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, document{name: name, body: body})
+	}
+
+	return docs, nil
+}
+
+func runFmt(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: xmlrpc fmt [flags] [file...]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Prints XML-RPC documents in canonical, indented form. Standard input is read if no files are given.")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+	write := fs.Bool("w", false, "write result to the file instead of standard output")
+
+	names, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if *write && len(names) == 0 {
+		return &usageError{msg: "-w requires files to be specified"}
+	}
+
+	docs, err := readDocuments(names, stdin)
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		out, err := xmlrpc.Format(doc.body)
+		if err != nil {
+			return fmt.Errorf("%s: %w", doc.name, err)
+		}
+
+		if *write {
+			if err := writeFile(doc.name, out); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := stdout.Write(out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runLint(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: xmlrpc lint [file...]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Reports violations of the XML-RPC specification. Standard input is read if no files are given.")
+		fmt.Fprintln(stderr, "Exit code is 1 if any violations are found.")
+	}
+
+	names, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	docs, err := readDocuments(names, stdin)
+	if err != nil {
+		return err
+	}
+
+	failed := false
+	for _, doc := range docs {
+		issues, err := xmlrpc.Lint(doc.body)
+		if err != nil {
+			fmt.Fprintf(stdout, "%s: %v\n", doc.name, err)
+			failed = true
+			continue
+		}
+
+		for _, issue := range issues {
+			if issue.Path == "" {
+				fmt.Fprintf(stdout, "%s:%d: %s\n", doc.name, issue.Line, issue.Message)
+			} else {
+				fmt.Fprintf(stdout, "%s:%d: %s: %s\n", doc.name, issue.Line, issue.Path, issue.Message)
+			}
+			failed = true
+		}
+	}
+
+	if failed {
+		return &exitCodeError{code: exitError}
+	}
+
+	return nil
+}
+
+// writeFile replaces contents of an existing file, keeping its permissions.
+func writeFile(name string, data []byte) error {
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(name, data, info.Mode().Perm())
+}
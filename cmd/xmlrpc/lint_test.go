@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validDoc   = `<methodResponse><params><param><value><int>5</int></value></param></params></methodResponse>`
+	invalidDoc = "<methodResponse>\n<params><param><value><array><value>1</value></array></value></param></params>\n</methodResponse>"
+)
+
+func TestRunFmt(t *testing.T) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	err := run([]string{"fmt"}, strings.NewReader(validDoc), stdout, stderr)
+	require.NoError(t, err)
+	require.Equal(t, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<methodResponse>\n  <params>\n    <param>\n      <value><int>5</int></value>\n    </param>\n  </params>\n</methodResponse>\n", stdout.String())
+
+	file := filepath.Join(t.TempDir(), "doc.xml")
+	require.NoError(t, os.WriteFile(file, []byte(validDoc), 0o600))
+
+	stdout.Reset()
+	err = run([]string{"fmt", "-w", file}, nil, stdout, stderr)
+	require.NoError(t, err)
+	require.Empty(t, stdout.String())
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(data), "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<methodResponse>\n  <params>\n"))
+
+	err = run([]string{"fmt"}, strings.NewReader("<methodResponse>"), stdout, stderr)
+	require.EqualError(t, err, "<stdin>: XML syntax error on line 1: unexpected EOF")
+
+	err = run([]string{"fmt", "-w"}, nil, stdout, stderr)
+	require.EqualError(t, err, "-w requires files to be specified")
+}
+
+func TestRunLint(t *testing.T) {
+	dir := t.TempDir()
+	valid, invalid := filepath.Join(dir, "valid.xml"), filepath.Join(dir, "invalid.xml")
+	require.NoError(t, os.WriteFile(valid, []byte(validDoc), 0o600))
+	require.NoError(t, os.WriteFile(invalid, []byte(invalidDoc), 0o600))
+
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	code := exitCode(run([]string{"lint", valid}, nil, stdout, stderr), stderr)
+	require.Equal(t, 0, code)
+	require.Empty(t, stdout.String())
+
+	code = exitCode(run([]string{"lint", valid, invalid}, nil, stdout, stderr), stderr)
+	require.Equal(t, exitError, code)
+	require.Equal(t, invalid+":2: params.0: unexpected element <value> in <array>\n"+invalid+":2: params.0: <array> without <data>\n", stdout.String())
+	require.Empty(t, stderr.String())
+
+	stdout.Reset()
+	code = exitCode(run([]string{"lint"}, strings.NewReader("<html/>"), stdout, stderr), stderr)
+	require.Equal(t, exitError, code)
+	require.Equal(t, "<stdin>: unexpected root element <html>, expected <methodCall> or <methodResponse>\n", stdout.String())
+}
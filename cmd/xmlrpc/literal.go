@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateTimeLayouts are accepted by "dt:" literals.
+var dateTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"20060102T15:04:05",
+	"2006-01-02",
+}
+
+// parseParams builds method arguments out of a JSON array, or a list of literals (see parseLiteral).
+func parseParams(argsJSON string, literals []string) ([]any, error) {
+	if argsJSON != "" {
+		v, err := parseJSON(argsJSON)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --args: %w", err)
+		}
+
+		params, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid --args: expected a JSON array, got %T", v)
+		}
+
+		return params, nil
+	}
+
+	params := make([]any, 0, len(literals))
+	for i, l := range literals {
+		v, err := parseLiteral(l)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %d: %w", i+1, err)
+		}
+		params = append(params, v)
+	}
+
+	return params, nil
+}
+
+// parseLiteral converts a command-line argument into a value. Arguments may be prefixed with a type:
+//
+//	i4:5, int:5           integer
+//	double:1.5            double
+//	bool:true, bool:1     boolean
+//	str:text              string (useful for strings looking like JSON or other literals)
+//	b64:aGk=, b64:@file   base64 of provided data, or contents of a file
+//	dt:2024-01-01T00:00:00  dateTime.iso8601
+//	json:{"a": 1}         JSON value
+//	nil                   <nil/>
+//
+// Arguments without a prefix are treated as JSON if valid, and as strings otherwise.
+func parseLiteral(s string) (any, error) {
+	if s == "nil" {
+		return nil, nil
+	}
+
+	if v, ok, err := parseTyped(s); ok {
+		return v, err
+	}
+
+	if v, err := parseJSON(s); err == nil {
+		return v, nil
+	}
+
+	return s, nil
+}
+
+// parseTyped parses literals with a type prefix. It reports false if the literal has no known prefix.
+func parseTyped(s string) (any, bool, error) {
+	prefix, value, found := strings.Cut(s, ":")
+	if !found {
+		return nil, false, nil
+	}
+
+	var v any
+	var err error
+
+	switch prefix {
+	case "i4", "int":
+		v, err = strconv.Atoi(value)
+	case "double":
+		v, err = strconv.ParseFloat(value, 64)
+	case "bool", "boolean":
+		v, err = strconv.ParseBool(value)
+	case "str", "string":
+		v = value
+	case "b64", "base64":
+		v, err = parseBase64(value)
+	case "dt":
+		v, err = parseDateTime(value)
+	case "json":
+		v, err = parseJSON(value)
+	default:
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid %s literal '%s': %w", prefix, value, err)
+	}
+
+	return v, true, nil
+}
+
+func parseBase64(value string) ([]byte, error) {
+	if path, ok := strings.CutPrefix(value, "@"); ok {
+		return os.ReadFile(path)
+	}
+
+	return base64.StdEncoding.DecodeString(value)
+}
+
+func parseDateTime(value string) (time.Time, error) {
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("expected one of layouts %s", strings.Join(dateTimeLayouts, ", "))
+}
+
+// parseJSON decodes JSON, keeping integers as int. Strings with a type prefix (see parseLiteral) are converted as well.
+func parseJSON(s string) (any, error) {
+	dec := json.NewDecoder(bytes.NewBufferString(s))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+
+	return fromJSON(v)
+}
+
+func fromJSON(v any) (any, error) {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := strconv.Atoi(val.String()); err == nil {
+			return i, nil
+		}
+		return val.Float64()
+
+	case string:
+		if typed, ok, err := parseTyped(val); ok {
+			return typed, err
+		}
+		return val, nil
+
+	case []any:
+		for i := range val {
+			item, err := fromJSON(val[i])
+			if err != nil {
+				return nil, err
+			}
+			val[i] = item
+		}
+		return val, nil
+
+	case map[string]any:
+		for k := range val {
+			item, err := fromJSON(val[k])
+			if err != nil {
+				return nil, err
+			}
+			val[k] = item
+		}
+		return val, nil
+
+	default:
+		return v, nil
+	}
+}
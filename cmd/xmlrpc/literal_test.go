@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLiteral(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data.bin")
+	require.NoError(t, os.WriteFile(file, []byte("file contents"), 0o600))
+
+	tests := []struct {
+		literal string
+		expect  any
+		err     string
+	}{
+		{literal: "5", expect: 5},
+		{literal: "-5.5", expect: -5.5},
+		{literal: "true", expect: true},
+		{literal: "hello world", expect: "hello world"},
+		{literal: `"5"`, expect: "5"},
+		{literal: "nil", expect: nil},
+		{literal: "i4:7", expect: 7},
+		{literal: "int:-7", expect: -7},
+		{literal: "double:2", expect: 2.0},
+		{literal: "bool:0", expect: false},
+		{literal: "str:i4:7", expect: "i4:7"},
+		{literal: "b64:aGVsbG8=", expect: []byte("hello")},
+		{literal: "b64:@" + file, expect: []byte("file contents")},
+		{literal: "dt:2024-01-02T03:04:05", expect: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{literal: "dt:20240102T03:04:05", expect: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{literal: "dt:2024-01-02T03:04:05+02:00", expect: time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 2*60*60))},
+		{literal: `{"a": [1, "i4:2", 1.5], "b": "dt:2024-01-02"}`, expect: map[string]any{
+			"a": []any{1, 2, 1.5},
+			"b": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		}},
+		{literal: `json:"quoted"`, expect: "quoted"},
+		{literal: "http://example.com", expect: "http://example.com"},
+		{literal: "i4:x", err: "invalid i4 literal 'x'"},
+		{literal: "dt:yesterday", err: "invalid dt literal 'yesterday': expected one of layouts"},
+		{literal: "b64:@/does/not/exist", err: "invalid b64 literal '@/does/not/exist'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.literal, func(t *testing.T) {
+			v, err := parseLiteral(tt.literal)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			if expected, ok := tt.expect.(time.Time); ok {
+				require.True(t, expected.Equal(v.(time.Time)), "expected %v, got %v", expected, v)
+				return
+			}
+			require.Equal(t, tt.expect, v)
+		})
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	params, err := parseParams(`[1, "b64:aGk="]`, nil)
+	require.NoError(t, err)
+	require.Equal(t, []any{1, []byte("hi")}, params)
+
+	_, err = parseParams(`{"a": 1}`, nil)
+	require.EqualError(t, err, "invalid --args: expected a JSON array, got map[string]interface {}")
+
+	_, err = parseParams(`[1] [2]`, nil)
+	require.EqualError(t, err, "invalid --args: unexpected data after JSON value")
+
+	params, err = parseParams("", []string{"a", "i4:1"})
+	require.NoError(t, err)
+	require.Equal(t, []any{"a", 1}, params)
+}
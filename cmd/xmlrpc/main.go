@@ -0,0 +1,198 @@
+// Command xmlrpc calls XML-RPC methods from the shell.
+//
+// Usage:
+//
+//	xmlrpc <command> [flags]
+//
+// Commands:
+//
+//	call   call a method: xmlrpc call [flags] URL method [args...]
+//	shell  start an interactive shell: xmlrpc shell [flags] URL
+//	fmt    print documents in canonical form: xmlrpc fmt [-w] [file...]
+//	lint   report specification violations of documents: xmlrpc lint [file...]
+//
+// Endpoints may use http://, https://, unix://, scgi:// and scgi+unix:// schemes.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Exit codes of the command.
+const (
+	exitError = 1
+	exitUsage = 2
+	exitFault = 3
+)
+
+// command is a single subcommand of the tool.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+var commands = []command{
+	{name: "call", usage: "call a method: xmlrpc call [flags] URL method [args...]", run: runCall},
+	{name: "shell", usage: "start an interactive shell: xmlrpc shell [flags] URL", run: runShell},
+	{name: "fmt", usage: "print documents in canonical form: xmlrpc fmt [-w] [file...]", run: runFmt},
+	{name: "lint", usage: "report specification violations of documents: xmlrpc lint [file...]", run: runLint},
+}
+
+// exitCodeError carries the exit code of the command, for errors already reported to the user.
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exit code %d", e.code)
+}
+
+// usageError is returned for invalid invocations of the command.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func main() {
+	os.Exit(exitCode(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr), os.Stderr))
+}
+
+func exitCode(err error, stderr io.Writer) int {
+	var codeErr *exitCodeError
+	var usageErr *usageError
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &codeErr):
+		return codeErr.code
+	case errors.Is(err, flag.ErrHelp):
+		return exitUsage
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(stderr, "xmlrpc: %v\n", err)
+		return exitUsage
+	default:
+		fmt.Fprintf(stderr, "xmlrpc: %v\n", err)
+		return exitError
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return &usageError{msg: "no command specified"}
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:], stdin, stdout, stderr)
+		}
+	}
+
+	printUsage(stderr)
+	return &usageError{msg: fmt.Sprintf("unknown command '%s'", args[0])}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: xmlrpc <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-6s %s\n", c.name, c.usage)
+	}
+}
+
+// parseInterspersed parses flags that may appear before, between or after positional arguments,
+// and returns the positional arguments. Arguments after "--" are always positional, as are negative numbers (e.g. -5 or -1.5)
+// that are not values of flags.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		// Flags are parsed up to the first negative number, which flag.FlagSet would take for a flag
+		end := len(args)
+		for i, arg := range args {
+			if arg == "--" {
+				break
+			}
+			if isNegativeNumber(arg) && !isFlagValue(fs, args[:i]) {
+				end = i
+				break
+			}
+		}
+
+		if err := fs.Parse(args[:end]); err != nil {
+			return nil, err
+		}
+
+		rest := fs.Args()
+		// Parse stops at "--", which is consumed, so check whether it was the terminator
+		if end > len(rest) && args[end-len(rest)-1] == "--" {
+			return append(positional, rest...), nil
+		}
+
+		if len(rest) == 0 {
+			if end == len(args) {
+				return positional, nil
+			}
+			positional = append(positional, args[end])
+			args = args[end+1:]
+			continue
+		}
+
+		positional = append(positional, rest[0])
+		args = append(append([]string{}, rest[1:]...), args[end:]...)
+	}
+}
+
+// isNegativeNumber reports whether arg is a negative number rather than a flag.
+func isNegativeNumber(arg string) bool {
+	if !strings.HasPrefix(arg, "-") {
+		return false
+	}
+	_, err := strconv.ParseFloat(arg, 64)
+
+	return err == nil
+}
+
+// isFlagValue reports whether the argument following preceding ones is the value of a flag (e.g. -n in "-n -5").
+func isFlagValue(fs *flag.FlagSet, preceding []string) bool {
+	if len(preceding) == 0 {
+		return false
+	}
+
+	name := preceding[len(preceding)-1]
+	if !strings.HasPrefix(name, "-") || strings.Contains(name, "=") || name == "-" || name == "--" {
+		return false
+	}
+	f := fs.Lookup(strings.TrimLeft(name, "-"))
+	if f == nil {
+		return false
+	}
+	if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+		return false
+	}
+
+	return true
+}
+
+// stringList is a flag that may be repeated.
+type stringList []string
+
+func (l *stringList) String() string {
+	return fmt.Sprint(*l)
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
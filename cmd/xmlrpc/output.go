@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"alexejk.io/go-xmlrpc"
+)
+
+// jsonValue converts a Value into a value that marshals into JSON, preserving the order of struct members.
+func jsonValue(v xmlrpc.Value) any {
+	switch v.Kind() {
+	case xmlrpc.KindInt:
+		i, _ := v.Int()
+		return i
+	case xmlrpc.KindDouble:
+		d, _ := v.Double()
+		return d
+	case xmlrpc.KindBoolean:
+		b, _ := v.Bool()
+		return b
+	case xmlrpc.KindString, xmlrpc.KindUntyped:
+		s, _ := v.Text()
+		return s
+	case xmlrpc.KindBase64:
+		b, err := v.Bytes()
+		if err != nil {
+			return v.String()
+		}
+		return b
+	case xmlrpc.KindDateTime:
+		t, err := v.Time()
+		if err != nil {
+			return v.String()
+		}
+		return t.Format(time.RFC3339)
+	case xmlrpc.KindArray:
+		items, _ := v.Array()
+		out := make([]any, len(items))
+		for i, item := range items {
+			out[i] = jsonValue(item)
+		}
+		return out
+	case xmlrpc.KindStruct:
+		return orderedObject(v.Members())
+	default:
+		return nil
+	}
+}
+
+// orderedObject marshals struct members into a JSON object in their original order.
+type orderedObject []xmlrpc.ValueMember
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(m.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(jsonValue(m.Value))
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// marshalJSON returns indented JSON of the value, followed by a newline.
+func marshalJSON(v any) ([]byte, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
@@ -0,0 +1,540 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"alexejk.io/go-xmlrpc"
+)
+
+const (
+	shellPrompt = "xmlrpc> "
+	// lastResult is the name of the variable holding the result of the last call.
+	lastResult = "_"
+
+	maxHistory  = 1000
+	maxLineSize = 16 * 1024 * 1024
+)
+
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+const shellHelp = `Commands:
+  method [args...]         call a method, arguments are literals (as in 'xmlrpc call') or $variables
+  name = method [args...]  call a method and store its result in $name
+  prefix?                  list methods starting with prefix, or show help of a single match
+  :help [method]           show this help, or signature and help of a method
+  :methods [prefix]        list methods of the server
+  :set name value          store a value in $name
+  :vars                    list variables
+  :history                 list history, !N repeats entry N and !! the last one
+  :quit                    exit the shell
+
+The result of the last call is stored in $_. Parts of values are referenced by paths, e.g. $bug.bugs.0.id
+Arguments containing spaces are quoted with single quotes, JSON values may span spaces.
+Lines are read as a whole, without line editing: Tab and arrow keys are not handled while typing, so methods are
+looked up with prefix? (or prefix<Tab><Enter>). Run the shell with a line editing wrapper (e.g. rlwrap) for these.
+`
+
+// shell is an interactive session with an endpoint.
+type shell struct {
+	client  *xmlrpc.Client
+	timeout time.Duration
+	out     io.Writer
+
+	methods []string
+	// vars hold results of calls as xmlrpc.Value, and values set with :set as parsed literals.
+	vars map[string]any
+
+	history     []string
+	historyFile io.Writer
+}
+
+func runShell(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("shell", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: xmlrpc shell [flags] URL")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+
+	conn := &connection{}
+	conn.register(fs)
+	historyPath := fs.String("history", defaultHistoryPath(), "file to keep history of commands in (empty to disable)")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		fs.Usage()
+		return &usageError{msg: "URL must be specified"}
+	}
+
+	client, _, err := conn.client(positional[0], stderr)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	sh := &shell{
+		client:  client,
+		timeout: conn.timeout,
+		out:     stdout,
+		vars:    make(map[string]any),
+	}
+
+	if *historyPath != "" {
+		f, err := sh.openHistory(*historyPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+	}
+
+	if err := sh.loadMethods(); err != nil {
+		fmt.Fprintf(stdout, "Connected to %s, method lookup is not available: %v\n", positional[0], err)
+	} else {
+		fmt.Fprintf(stdout, "Connected to %s, %d methods available.\n", positional[0], len(sh.methods))
+	}
+	fmt.Fprintln(stdout, "Type :help for help.")
+
+	return sh.run(stdin)
+}
+
+func defaultHistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".xmlrpc_history")
+}
+
+// openHistory loads previous history from the file, and opens it for appending new entries.
+func (s *shell) openHistory(path string) (*os.File, error) {
+	if data, err := os.ReadFile(path); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if line != "" {
+				s.history = append(s.history, line)
+			}
+		}
+		if len(s.history) > maxHistory {
+			s.history = s.history[len(s.history)-maxHistory:]
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:mnd // private to the user
+	if err != nil {
+		return nil, fmt.Errorf("cannot open history file: %w", err)
+	}
+	s.historyFile = f
+
+	return f, nil
+}
+
+func (s *shell) loadMethods() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	methods, err := xmlrpc.Invoke[[]string](ctx, s.client, "system.listMethods", nil)
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(methods)
+	s.methods = methods
+
+	return nil
+}
+
+// run reads and executes lines until the input ends or the shell is quit.
+func (s *shell) run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(nil, maxLineSize)
+
+	for {
+		fmt.Fprint(s.out, shellPrompt)
+		if !scanner.Scan() {
+			fmt.Fprintln(s.out)
+			return scanner.Err()
+		}
+
+		// Without a raw terminal, Tab is received as part of the line once Enter is pressed
+		raw := strings.TrimRight(scanner.Text(), "\r\n ")
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+
+		if !strings.ContainsAny(line, " \t") && (strings.HasSuffix(raw, "\t") || strings.HasSuffix(line, "?")) {
+			s.complete(strings.TrimSuffix(line, "?"))
+			continue
+		}
+
+		line, err := s.expandHistory(line)
+		if err != nil {
+			fmt.Fprintf(s.out, "error: %v\n", err)
+			continue
+		}
+		s.addHistory(line)
+
+		quit, err := s.exec(line)
+		if err != nil {
+			fmt.Fprintf(s.out, "error: %v\n", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// expandHistory replaces !! and !N with the respective history entry.
+func (s *shell) expandHistory(line string) (string, error) {
+	if !strings.HasPrefix(line, "!") {
+		return line, nil
+	}
+
+	n := len(s.history)
+	if line != "!!" {
+		var err error
+		if n, err = strconv.Atoi(line[1:]); err != nil {
+			return "", fmt.Errorf("invalid history reference '%s'", line)
+		}
+	}
+
+	if n < 1 || n > len(s.history) {
+		return "", fmt.Errorf("no history entry %d", n)
+	}
+
+	entry := s.history[n-1]
+	fmt.Fprintln(s.out, entry)
+
+	return entry, nil
+}
+
+func (s *shell) addHistory(line string) {
+	s.history = append(s.history, line)
+	if s.historyFile != nil {
+		_, _ = fmt.Fprintln(s.historyFile, line)
+	}
+}
+
+// exec executes a single line, returning true if the shell should be quit.
+func (s *shell) exec(line string) (bool, error) {
+	if strings.HasPrefix(line, ":") {
+		return s.execCommand(line)
+	}
+
+	tokens, err := splitArgs(line)
+	if err != nil {
+		return false, err
+	}
+
+	target := lastResult
+	if len(tokens) > 1 && tokens[1] == "=" {
+		target = strings.TrimPrefix(tokens[0], "$")
+		if !variableName.MatchString(target) {
+			return false, fmt.Errorf("invalid variable name '%s'", tokens[0])
+		}
+		tokens = tokens[2:]
+		if len(tokens) == 0 {
+			return false, errors.New("method must be specified")
+		}
+	}
+
+	params := make([]any, len(tokens)-1)
+	for i, token := range tokens[1:] {
+		if params[i], err = s.resolveArg(token); err != nil {
+			return false, fmt.Errorf("invalid argument %d: %w", i+1, err)
+		}
+	}
+
+	return false, s.call(tokens[0], params, target)
+}
+
+func (s *shell) call(method string, params []any, target string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	var result []xmlrpc.Value
+	err := s.client.CallContext(ctx, method, xmlrpc.Args(params), &result)
+
+	fault := &xmlrpc.Fault{}
+	if errors.As(err, &fault) {
+		fmt.Fprintf(s.out, "fault %d: %s\n", fault.Code, fault.String)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Responses have a single param, which is the one stored
+	if len(result) == 1 {
+		s.vars[lastResult] = result[0]
+		s.vars[target] = result[0]
+	}
+
+	return writeResult(s.out, formatJSON, nil, result, nil, false)
+}
+
+// resolveArg converts an argument into a value, looking up $variables and their paths.
+func (s *shell) resolveArg(token string) (any, error) {
+	if !strings.HasPrefix(token, "$") {
+		return parseLiteral(token)
+	}
+
+	name, path, _ := strings.Cut(token[1:], ".")
+	v, ok := s.vars[name]
+	if !ok {
+		return nil, fmt.Errorf("undefined variable '$%s'", name)
+	}
+
+	if path == "" {
+		return v, nil
+	}
+
+	value, ok := v.(xmlrpc.Value)
+	if !ok {
+		return nil, fmt.Errorf("variable '$%s' is not a call result, paths cannot be used", name)
+	}
+
+	return value.Path(path)
+}
+
+func (s *shell) execCommand(line string) (bool, error) {
+	name, rest, _ := strings.Cut(line[1:], " ")
+	rest = strings.TrimSpace(rest)
+
+	switch name {
+	case "quit", "exit", "q":
+		return true, nil
+
+	case "help", "h":
+		if rest == "" {
+			fmt.Fprint(s.out, shellHelp)
+			return false, nil
+		}
+		return false, s.help(rest)
+
+	case "methods":
+		for _, m := range s.matchMethods(rest) {
+			fmt.Fprintln(s.out, m)
+		}
+
+	case "set":
+		target, literal, _ := strings.Cut(rest, " ")
+		target = strings.TrimPrefix(target, "$")
+		if !variableName.MatchString(target) || strings.TrimSpace(literal) == "" {
+			return false, errors.New("usage: :set name value")
+		}
+		return false, s.set(target, strings.TrimSpace(literal))
+
+	case "vars":
+		s.printVars()
+
+	case "history":
+		for i, entry := range s.history {
+			fmt.Fprintf(s.out, "%4d  %s\n", i+1, entry)
+		}
+
+	default:
+		return false, fmt.Errorf("unknown command ':%s', see :help", name)
+	}
+
+	return false, nil
+}
+
+// set stores a literal (or another variable) as a variable.
+func (s *shell) set(name, literal string) error {
+	tokens, err := splitArgs(literal)
+	if err != nil {
+		return err
+	}
+	if len(tokens) != 1 {
+		return errors.New("a single value is expected")
+	}
+
+	arg, err := s.resolveArg(tokens[0])
+	if err != nil {
+		return err
+	}
+
+	s.vars[name] = arg
+
+	return nil
+}
+
+func (s *shell) printVars() {
+	names := make([]string, 0, len(s.vars))
+	for name := range s.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v := s.vars[name]
+		if value, ok := v.(xmlrpc.Value); ok {
+			v = jsonValue(value)
+		}
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			b = []byte(fmt.Sprint(v))
+		}
+		fmt.Fprintf(s.out, "$%s = %s\n", name, b)
+	}
+}
+
+// complete lists methods starting with the prefix, showing help of the method when there is a single one.
+// It runs once the line is entered, as the shell does not read keys of a raw terminal.
+func (s *shell) complete(prefix string) {
+	matches := s.matchMethods(prefix)
+
+	switch {
+	case len(matches) == 0:
+		fmt.Fprintf(s.out, "no methods matching '%s'\n", prefix)
+	case len(matches) == 1 || matches[0] == prefix:
+		if matches[0] != prefix {
+			fmt.Fprintln(s.out, matches[0])
+		}
+		if err := s.help(matches[0]); err != nil {
+			fmt.Fprintf(s.out, "error: %v\n", err)
+		}
+	default:
+		fmt.Fprintln(s.out, strings.Join(matches, "  "))
+	}
+}
+
+func (s *shell) matchMethods(prefix string) []string {
+	var matches []string
+	for _, m := range s.methods {
+		if strings.HasPrefix(m, prefix) {
+			matches = append(matches, m)
+		}
+	}
+
+	return matches
+}
+
+// help prints signatures (system.methodSignature) and help (system.methodHelp) of a method.
+func (s *shell) help(method string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	if sigs, err := xmlrpc.Invoke[xmlrpc.Value](ctx, s.client, "system.methodSignature", xmlrpc.Args{method}); err == nil {
+		for _, sig := range formatSignatures(method, sigs) {
+			fmt.Fprintln(s.out, sig)
+		}
+	}
+
+	help, err := xmlrpc.Invoke[string](ctx, s.client, "system.methodHelp", xmlrpc.Args{method})
+	if err != nil {
+		return fmt.Errorf("cannot get help of '%s': %w", method, err)
+	}
+
+	if help = strings.TrimSpace(help); help != "" {
+		fmt.Fprintln(s.out, help)
+	}
+
+	return nil
+}
+
+// formatSignatures formats signatures reported by system.methodSignature as "method(params...) result".
+// Servers not knowing signatures of a method report a non-array value, for which nothing is returned.
+func formatSignatures(method string, sigs xmlrpc.Value) []string {
+	items, err := sigs.Array()
+	if err != nil {
+		return nil
+	}
+
+	var out []string
+	for _, item := range items {
+		types, err := item.Array()
+		if err != nil || len(types) == 0 {
+			continue
+		}
+
+		names := make([]string, len(types))
+		for i, t := range types {
+			names[i], _ = t.Text()
+		}
+		out = append(out, fmt.Sprintf("%s(%s) %s", method, strings.Join(names[1:], ", "), names[0]))
+	}
+
+	return out
+}
+
+// splitArgs splits a line into whitespace separated arguments. Single quotes group words into a
+// single argument and are removed, while double quotes and JSON arrays and objects are kept intact.
+func splitArgs(line string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inToken bool
+		escaped bool
+		quote   rune
+		depth   int
+	)
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0 && r == quote:
+			quote = 0
+			if r == '\'' {
+				continue
+			}
+		case quote != 0:
+		case r == '"':
+			quote = r
+		case r == '\'' && depth == 0:
+			quote = r
+			inToken = true
+			continue
+		case r == '{' || r == '[':
+			depth++
+		case (r == '}' || r == ']') && depth > 0:
+			depth--
+		case unicode.IsSpace(r) && depth == 0:
+			if inToken {
+				args = append(args, current.String())
+				current.Reset()
+				inToken = false
+			}
+			continue
+		}
+
+		inToken = true
+		current.WriteRune(r)
+	}
+
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if depth != 0 {
+		return nil, errors.New("unbalanced brackets")
+	}
+	if inToken {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunShell(t *testing.T) {
+	ts := testServer(t)
+	defer ts.Close()
+
+	tests := []struct {
+		name   string
+		input  string
+		output string
+	}{
+		{
+			name:   "call",
+			input:  "sample.add 2 3\n",
+			output: "xmlrpc> 5\nxmlrpc> \n",
+		},
+		{
+			name:   "pretty-printed struct",
+			input:  "sample.user\n",
+			output: "xmlrpc> {\n  \"login\": \"alice\",\n  \"created\": \"2024-01-02T03:04:05Z\",\n  \"groups\": [\n    \"admin\"\n  ]\n}\nxmlrpc> \n",
+		},
+		{
+			name:   "completion",
+			input:  "sample.?\nsample.ad\t\nsystem.methodHelp?\nfoo?\n",
+			output: "xmlrpc> sample.add  sample.echo  sample.fail  sample.user\nxmlrpc> sample.add\nsample.add(int, int) int\nHelp of sample.add.\nxmlrpc> Help of system.methodHelp.\nxmlrpc> no methods matching 'foo'\nxmlrpc> \n",
+		},
+		{
+			name:   "variables",
+			input:  "user = sample.user\nsample.echo $user.login $_.groups.0 '2 words'\n:set n i4:5\nsample.add $n $n\n:vars\n",
+			output: "xmlrpc> {\n  \"login\": \"alice\",\n  \"created\": \"2024-01-02T03:04:05Z\",\n  \"groups\": [\n    \"admin\"\n  ]\n}\nxmlrpc> [\n  \"alice\",\n  \"admin\",\n  \"2 words\"\n]\nxmlrpc> xmlrpc> 10\nxmlrpc> $_ = 10\n$n = 5\n$user = {\"login\":\"alice\",\"created\":\"2024-01-02T03:04:05Z\",\"groups\":[\"admin\"]}\nxmlrpc> \n",
+		},
+		{
+			name:   "json arguments",
+			input:  `sample.echo {"a": [1, 2]} "quoted text"` + "\n",
+			output: "xmlrpc> [\n  {\n    \"a\": [\n      1,\n      2\n    ]\n  },\n  \"quoted text\"\n]\nxmlrpc> \n",
+		},
+		{
+			name:   "history",
+			input:  "sample.add 1 2\n!!\n:history\n!1\n!9\n",
+			output: "xmlrpc> 3\nxmlrpc> sample.add 1 2\n3\nxmlrpc>    1  sample.add 1 2\n   2  sample.add 1 2\n   3  :history\nxmlrpc> sample.add 1 2\n3\nxmlrpc> error: no history entry 9\nxmlrpc> \n",
+		},
+		{
+			name:   "errors",
+			input:  "sample.fail\nsample.add $missing 1\n:unknown\nsample.echo '\n",
+			output: "xmlrpc> fault 42: failed on purpose\nxmlrpc> error: invalid argument 1: undefined variable '$missing'\nxmlrpc> error: unknown command ':unknown', see :help\nxmlrpc> error: unterminated quote\nxmlrpc> \n",
+		},
+		{
+			name:   "quit",
+			input:  ":quit\nsample.add 1 2\n",
+			output: "xmlrpc> ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+			err := run([]string{"shell", "-history", "", ts.URL}, strings.NewReader(tt.input), stdout, stderr)
+			require.NoError(t, err, stderr.String())
+
+			banner := "Connected to " + ts.URL + ", 7 methods available.\nType :help for help.\n"
+			require.Equal(t, banner+tt.output, stdout.String())
+		})
+	}
+}
+
+func TestRunShell_HistoryFile(t *testing.T) {
+	ts := testServer(t)
+	defer ts.Close()
+
+	history := filepath.Join(t.TempDir(), "history")
+	require.NoError(t, os.WriteFile(history, []byte("sample.add 2 2\n"), 0o600))
+
+	stdout := new(bytes.Buffer)
+	err := run([]string{"shell", "-history", history, ts.URL}, strings.NewReader("!1\nsample.add 1 1\n"), stdout, new(bytes.Buffer))
+	require.NoError(t, err)
+	require.Contains(t, stdout.String(), "xmlrpc> sample.add 2 2\n4\nxmlrpc> 2\n")
+
+	data, err := os.ReadFile(history)
+	require.NoError(t, err)
+	require.Equal(t, "sample.add 2 2\nsample.add 2 2\nsample.add 1 1\n", string(data))
+}
+
+func Test_splitArgs(t *testing.T) {
+	tests := []struct {
+		line   string
+		expect []string
+		err    string
+	}{
+		{line: "a  b\tc", expect: []string{"a", "b", "c"}},
+		{line: `m 'two words' "json string" str:x`, expect: []string{"m", "two words", `"json string"`, "str:x"}},
+		{line: `m {"a": [1, "b c"], "d": "it's"} [1, 2]`, expect: []string{"m", `{"a": [1, "b c"], "d": "it's"}`, "[1, 2]"}},
+		{line: `m "escaped \" quote"`, expect: []string{"m", `"escaped \" quote"`}},
+		{line: "m ''", expect: []string{"m", ""}},
+		{line: "m 'open", err: "unterminated quote"},
+		{line: "m [1, 2", err: "unbalanced brackets"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			args, err := splitArgs(tt.line)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, args)
+		})
+	}
+}
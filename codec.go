@@ -28,6 +28,7 @@ type Codec struct {
 
 	// Current in-flight response
 	response *Response
+	inflight *rpcCall
 	encoder  Encoder
 	decoder  Decoder
 
@@ -35,13 +36,43 @@ type Codec struct {
 	ready chan uint64
 
 	userAgent string
-	shutdown  chan struct{}
+	limits    limits
+	streaming bool
+	// requests are encoded while being sent, rather than up front
+	streamRequests bool
+	// response streamed from its body, when decoded in ReadResponseBody
+	stream       *responseStream
+	streamBody   io.Closer
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
 }
 
 type rpcCall struct {
 	Seq           uint64
 	ServiceMethod string
 	httpResponse  *http.Response
+	callContext   *callContext
+}
+
+// fail reports an error of the call to rpc.Client, while retaining the original error for the caller (if known).
+func (call *rpcCall) fail(resp *rpc.Response, err error) {
+	resp.Error = err.Error()
+	if call.callContext != nil {
+		call.callContext.err = err
+	}
+}
+
+// callContext wraps arguments of a call made with a context.
+// As rpc.Client only passes through arguments to the codec, this is the way to provide the context for the HTTP request
+// and to return original errors (e.g. *Fault) to the caller, instead of rpc.ServerError.
+//
+// Reply of such calls is decoded while reading the response header, as rpc.Client considers any error
+// returned by ReadResponseBody fatal and shuts down.
+type callContext struct {
+	ctx   context.Context
+	args  interface{}
+	reply interface{}
+	err   error
 }
 
 // NewCodec creates a new Codec bound to provided endpoint.
@@ -73,18 +104,29 @@ func (c *Codec) SetDecoder(decoder Decoder) {
 }
 
 func (c *Codec) WriteRequest(req *rpc.Request, args interface{}) error {
-	bodyBuffer := new(bytes.Buffer)
-	err := c.encoder.Encode(bodyBuffer, req.ServiceMethod, args)
+	ctx := context.TODO()
+	cc, hasContext := args.(*callContext)
+	if hasContext {
+		ctx, args = cc.ctx, cc.args
+	}
+
+	body, encoded, err := c.encodeRequest(req.ServiceMethod, args)
 	if err != nil {
 		return err
 	}
 
-	httpRequest, err := http.NewRequestWithContext(context.TODO(), "POST", c.endpoint.String(), bodyBuffer)
+	httpRequest, err := http.NewRequestWithContext(ctx, "POST", c.endpoint.String(), body)
 	if err != nil {
+		_ = encoded()
 		return err
 	}
 
-	httpRequest.Header.Set("Content-Type", "text/xml")
+	contentType := "text/xml"
+	if e, ok := c.encoder.(*StdEncoder); ok {
+		contentType = e.contentType()
+	}
+
+	httpRequest.Header.Set("Content-Type", contentType)
 	httpRequest.Header.Set("User-Agent", c.userAgent)
 
 	// Apply customer headers if set, this allows overwriting static default headers
@@ -92,10 +134,17 @@ func (c *Codec) WriteRequest(req *rpc.Request, args interface{}) error {
 		httpRequest.Header.Set(key, value)
 	}
 
-	httpRequest.Header.Set("Content-Length", fmt.Sprintf("%d", bodyBuffer.Len()))
+	// Streamed requests are sent with chunked transfer encoding, as their length is not known up front
+	if buf, ok := body.(*bytes.Buffer); ok {
+		httpRequest.Header.Set("Content-Length", fmt.Sprintf("%d", buf.Len()))
+	}
 
 	httpResponse, err := c.httpClient.Do(httpRequest) //nolint:bodyclose // Handled in ReadResponseHeader
-	if err != nil {
+	if encodeErr := encoded(); err != nil {
+		// Failure of encoding aborts a streamed request, and is the cause to report
+		if encodeErr != nil && !errors.Is(encodeErr, io.ErrClosedPipe) {
+			return encodeErr
+		}
 		return err
 	}
 
@@ -104,6 +153,7 @@ func (c *Codec) WriteRequest(req *rpc.Request, args interface{}) error {
 		Seq:           req.Seq,
 		ServiceMethod: req.ServiceMethod,
 		httpResponse:  httpResponse,
+		callContext:   cc,
 	}
 	c.mutex.Unlock()
 
@@ -112,6 +162,33 @@ func (c *Codec) WriteRequest(req *rpc.Request, args interface{}) error {
 	return nil
 }
 
+// encodeRequest returns body of a request. Unless requests are streamed, the body is encoded into a buffer up front.
+// Streamed bodies are encoded while being read, and encoded waits until the encoding stops, returning its error.
+func (c *Codec) encodeRequest(methodName string, args interface{}) (body io.Reader, encoded func() error, err error) {
+	if !c.streamRequests {
+		buf := new(bytes.Buffer)
+		if err := c.encoder.Encode(buf, methodName, args); err != nil {
+			return nil, nil, err
+		}
+
+		return buf, func() error { return nil }, nil
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := c.encoder.Encode(pw, methodName, args)
+		_ = pw.CloseWithError(err)
+		done <- err
+	}()
+
+	return pr, func() error {
+		// Encoding stops once the body is no longer read, e.g. when the request fails
+		_ = pr.Close()
+		return <-done
+	}, nil
+}
+
 func (c *Codec) ReadResponseHeader(resp *rpc.Response) error {
 	select {
 	case seq := <-c.ready:
@@ -126,32 +203,55 @@ func (c *Codec) ReadResponseHeader(resp *rpc.Response) error {
 
 		r := call.httpResponse
 
-		defer r.Body.Close()
-
 		if r.StatusCode < 200 || r.StatusCode >= 300 {
-			resp.Error = fmt.Sprintf("bad response code: %d", r.StatusCode)
+			_ = r.Body.Close()
+			call.fail(resp, fmt.Errorf("bad response code: %d", r.StatusCode))
+			return nil
+		}
+
+		if d, ok := c.decoder.(*StdDecoder); ok && c.streaming {
+			c.readStreamHeader(d, call, resp)
 			return nil
 		}
 
-		body, err := io.ReadAll(r.Body)
+		defer r.Body.Close()
+
+		body, err := c.limits.readBody(r.Body)
 		if err != nil {
-			resp.Error = err.Error()
+			call.fail(resp, err)
+			return nil
+		}
+
+		if d, ok := c.decoder.(*StdDecoder); ok && d.repair != nil {
+			body = d.repairBody(body)
+		}
+
+		if err := c.limits.check(body); err != nil {
+			call.fail(resp, err)
 			return nil
 		}
 
 		decodableResponse, err := NewResponse(body)
 		if err != nil {
-			resp.Error = err.Error()
+			call.fail(resp, err)
 			return nil
 		}
 
 		// Return response Fault already at this stage
-		if err := c.decoder.DecodeFault(decodableResponse); err != nil {
-			resp.Error = err.Error()
+		if fault := c.decoder.DecodeFault(decodableResponse); fault != nil {
+			call.fail(resp, fault)
 			return nil
 		}
 
 		c.response = decodableResponse
+		c.inflight = call
+
+		if cc := call.callContext; cc != nil && cc.reply != nil {
+			if err := c.decoder.Decode(decodableResponse, cc.reply); err != nil {
+				call.fail(resp, err)
+			}
+		}
+
 		return nil
 
 	case <-c.shutdown:
@@ -159,11 +259,72 @@ func (c *Codec) ReadResponseHeader(resp *rpc.Response) error {
 		return net.ErrClosed
 	}
 }
+
+// readStreamHeader reads the response body up to its params, which are then decoded directly from the body:
+// for calls with a context right away, otherwise in ReadResponseBody. The body is closed once the response is decoded.
+func (c *Codec) readStreamHeader(d *StdDecoder, call *rpcCall, resp *rpc.Response) {
+	body := call.httpResponse.Body
+	r := c.limits.reader(body)
+
+	// Repairs need the whole body
+	if d.repair != nil {
+		b, err := c.limits.readBody(body)
+		if err != nil {
+			_ = body.Close()
+			call.fail(resp, err)
+			return
+		}
+		r = bytes.NewReader(d.repairBody(b))
+	}
+
+	stream := d.newResponseStream(r, &c.limits)
+
+	fault, err := stream.readHeader()
+	if err == nil && fault != nil {
+		err = fault
+	}
+	if err != nil {
+		_ = body.Close()
+		call.fail(resp, err)
+		return
+	}
+
+	c.response = nil
+	c.inflight = call
+
+	if cc := call.callContext; cc != nil && cc.reply != nil {
+		defer body.Close()
+		if err := stream.decodeParams(cc.reply); err != nil {
+			call.fail(resp, err)
+		}
+		return
+	}
+
+	c.stream, c.streamBody = stream, body
+}
+
 func (c *Codec) ReadResponseBody(v interface{}) error {
+	if c.stream != nil {
+		stream, body := c.stream, c.streamBody
+		c.stream, c.streamBody = nil, nil
+		defer body.Close()
+
+		if v == nil {
+			return nil
+		}
+
+		return stream.decodeParams(v)
+	}
+
 	if v == nil {
 		return nil
 	}
 
+	// Already decoded while reading the header
+	if c.inflight != nil && c.inflight.callContext != nil && c.inflight.callContext.reply != nil {
+		return nil
+	}
+
 	if c.response == nil {
 		return errors.New("no in-flight response found")
 	}
@@ -172,7 +333,9 @@ func (c *Codec) ReadResponseBody(v interface{}) error {
 }
 
 func (c *Codec) Close() error {
-	c.shutdown <- struct{}{}
+	c.shutdownOnce.Do(func() {
+		close(c.shutdown)
+	})
 	c.httpClient.CloseIdleConnections()
 	return nil
 }
@@ -0,0 +1,319 @@
+package codegen
+
+import (
+	"go/token"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// GenerateService generates source of a Go package for the service described by the schema, containing:
+//
+//   - a Go type per declared struct type, and a constant per fault code;
+//   - a typed client (<Service>Client) performing calls with xmlrpc.Client;
+//   - a handler interface (<Service>Handler) and a function (Register<Service>) registering its implementation on xmlrpc.Server.
+//
+// Config.TypeName is not used, as names are derived from the service name.
+func GenerateService(schema *Schema, cfg Config) ([]byte, error) {
+	cfg = cfg.withDefaults()
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+
+	service := goIdentifier(schema.Service)
+	data := &serviceTemplateData{
+		Package: cfg.Package,
+		Service: service,
+		Doc:     docComment(schema.Doc),
+	}
+
+	for _, f := range schema.Faults {
+		data.Faults = append(data.Faults, serviceFault{
+			GoName: "Fault" + goIdentifier(f.Name),
+			Code:   f.Code,
+			Doc:    docComment(f.Doc),
+		})
+	}
+
+	for _, t := range schema.Types {
+		st := serviceType{GoName: goIdentifier(t.Name), Doc: docComment(t.Doc)}
+		for _, f := range t.Fields {
+			st.Fields = append(st.Fields, serviceField{
+				GoName: goIdentifier(f.Name),
+				Name:   f.Name,
+				Type:   schemaGoType(f.Type, f.Optional),
+				Doc:    docComment(f.Doc),
+			})
+		}
+		data.Types = append(data.Types, st)
+	}
+
+	names := newNameSet()
+	for _, m := range schema.Methods {
+		sm := serviceMethod{
+			GoName: names.claim(goIdentifier(m.Name)),
+			Name:   m.Name,
+			Doc:    docComment(m.Doc),
+		}
+		if m.Returns != "" {
+			sm.Reply = schemaGoType(m.Returns, false)
+		}
+		for _, f := range m.Faults {
+			sm.Faults = append(sm.Faults, "Fault"+goIdentifier(f))
+		}
+		for _, p := range m.Params {
+			sm.Params = append(sm.Params, serviceParam{
+				Name:     paramName(p.Name),
+				Field:    goIdentifier(p.Name),
+				Type:     schemaGoType(p.Type, p.Optional),
+				Optional: p.Optional,
+			})
+			sm.HasOptional = sm.HasOptional || p.Optional
+		}
+		data.Methods = append(data.Methods, sm)
+	}
+
+	data.NeedsTime = data.usesType(timeGoType)
+
+	return render(serviceTemplate, data)
+}
+
+type serviceTemplateData struct {
+	Package   string
+	Service   string
+	Doc       []string
+	NeedsTime bool
+	Faults    []serviceFault
+	Types     []serviceType
+	Methods   []serviceMethod
+}
+
+// usesType reports whether any of generated fields, params or replies refers to provided Go type.
+func (d *serviceTemplateData) usesType(goType string) bool {
+	refers := func(t string) bool {
+		return strings.TrimLeft(t, "[]*") == goType
+	}
+
+	for _, t := range d.Types {
+		for _, f := range t.Fields {
+			if refers(f.Type) {
+				return true
+			}
+		}
+	}
+	for _, m := range d.Methods {
+		if refers(m.Reply) {
+			return true
+		}
+		for _, p := range m.Params {
+			if refers(p.Type) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type serviceFault struct {
+	GoName string
+	Code   int
+	Doc    []string
+}
+
+type serviceType struct {
+	GoName string
+	Doc    []string
+	Fields []serviceField
+}
+
+type serviceField struct {
+	GoName string
+	Name   string
+	Type   string
+	Doc    []string
+}
+
+type serviceMethod struct {
+	GoName string
+	Name   string
+	Doc    []string
+	Params []serviceParam
+	Reply  string
+	Faults []string
+	// HasOptional is set when any of params is optional, so that arguments are passed as a struct omitting trailing zero values
+	HasOptional bool
+}
+
+type serviceParam struct {
+	// Name is the name of the argument of generated functions
+	Name string
+	// Field is the name of the field in the params struct used for registration on the server
+	Field    string
+	Type     string
+	Optional bool
+}
+
+var serviceTemplate = template.Must(template.New("service").Parse(`// Code generated by xmlrpc-gen. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"context"
+{{- if .NeedsTime }}
+	"time"
+{{- end }}
+
+	"alexejk.io/go-xmlrpc"
+)
+{{- if .Faults }}
+
+// Fault codes of {{ .Service }} service.
+const (
+{{- range .Faults }}
+{{- range .Doc }}
+	//{{ if . }} {{ . }}{{ end }}
+{{- end }}
+	{{ .GoName }} = {{ .Code }}
+{{- end }}
+)
+{{- end }}
+{{ range .Types }}
+{{- if .Doc }}
+{{ range .Doc }}
+//{{ if . }} {{ . }}{{ end }}
+{{- end }}
+{{- else }}
+// {{ .GoName }} is a struct of {{ $.Service }} service.
+{{- end }}
+type {{ .GoName }} struct {
+{{- range .Fields }}
+{{- range .Doc }}
+	//{{ if . }} {{ . }}{{ end }}
+{{- end }}
+	{{ .GoName }} {{ .Type }} ` + "`" + `xmlrpc:"{{ .Name }}"` + "`" + `
+{{- end }}
+}
+{{ end }}
+// {{ .Service }}Client provides typed methods of {{ .Service }} service.
+{{- if .Doc }}
+//
+{{- range .Doc }}
+//{{ if . }} {{ . }}{{ end }}
+{{- end }}
+{{- end }}
+type {{ .Service }}Client struct {
+	client *xmlrpc.Client
+}
+
+// New{{ .Service }}Client creates a {{ .Service }}Client that performs calls with provided client.
+func New{{ .Service }}Client(client *xmlrpc.Client) *{{ .Service }}Client {
+	return &{{ .Service }}Client{client: client}
+}
+{{ range .Methods }}
+// {{ .GoName }} calls remote method "{{ .Name }}".
+{{- template "methodDoc" . }}
+func (c *{{ $.Service }}Client) {{ .GoName }}({{ template "params" . }}) {{ template "results" . }} {
+{{- if .Reply }}
+	reply, err := xmlrpc.Invoke[struct{ Result {{ .Reply }} }](ctx, c.client, {{ printf "%q" .Name }}, {{ template "args" . }})
+	return reply.Result, err
+{{- else }}
+	return c.client.CallContext(ctx, {{ printf "%q" .Name }}, {{ template "args" . }}, nil)
+{{- end }}
+}
+{{ end }}
+// {{ .Service }}Handler is implemented by servers of {{ .Service }} service.
+type {{ .Service }}Handler interface {
+{{- range $i, $m := .Methods }}
+{{- if $i }}
+{{ end }}
+	// {{ .GoName }} handles remote method "{{ .Name }}".
+{{- if .Doc }}
+	//
+{{- end }}
+{{- range .Doc }}
+	//{{ if . }} {{ . }}{{ end }}
+{{- end }}
+	{{ .GoName }}({{ template "params" . }}) {{ template "results" . }}
+{{- end }}
+}
+
+// Register{{ .Service }} registers methods of {{ .Service }} service, implemented by h, on provided server.
+func Register{{ .Service }}(s *xmlrpc.Server, h {{ .Service }}Handler) error {
+{{- range .Methods }}
+	if err := s.Register({{ printf "%q" .Name }}, func(ctx context.Context{{ if .Params }}, args struct {
+	{{- range .Params }}
+		{{ .Field }} {{ .Type }}{{ if .Optional }} ` + "`" + `xmlrpc:",omitempty"` + "`" + `{{ end }}
+	{{- end }}
+	}{{ end }}) {{ template "results" . }} {
+		return h.{{ .GoName }}(ctx{{ range .Params }}, args.{{ .Field }}{{ end }})
+	}); err != nil {
+		return err
+	}
+{{- end }}
+
+	return nil
+}
+
+{{- define "methodDoc" }}
+{{- if .Doc }}
+//
+{{- range .Doc }}
+//{{ if . }} {{ . }}{{ end }}
+{{- end }}
+{{- end }}
+{{- if .Faults }}
+//
+// May fail with faults: {{ range $i, $f := .Faults }}{{ if $i }}, {{ end }}{{ $f }}{{ end }}.
+{{- end }}
+{{- end }}
+
+{{- define "params" }}ctx context.Context{{ range .Params }}, {{ .Name }} {{ .Type }}{{ end }}{{ end }}
+
+{{- define "results" }}{{ if .Reply }}({{ .Reply }}, error){{ else }}error{{ end }}{{ end }}
+
+{{- define "args" }}{{ if .HasOptional }}struct {
+	{{- range .Params }}
+		{{ .Field }} {{ .Type }}{{ if .Optional }} ` + "`" + `xmlrpc:",omitempty"` + "`" + `{{ end }}
+	{{- end }}
+	}{ {{- range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ $p.Name }}{{ end -}} }{{ else if .Params }}xmlrpc.Args{ {{- range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ $p.Name }}{{ end -}} }{{ else }}nil{{ end }}{{ end }}
+`))
+
+// schemaGoType converts a type reference of the schema into a Go type.
+func schemaGoType(ref string, optional bool) string {
+	var prefix string
+	for strings.HasPrefix(ref, "[]") {
+		prefix += "[]"
+		ref = strings.TrimPrefix(ref, "[]")
+	}
+
+	goType, ok := goTypes[ref]
+	if !ok {
+		goType, ok = idlTypes[ref]
+	}
+	if !ok {
+		goType = goIdentifier(ref)
+	}
+
+	// Slices and maps are nil-able already, while Value tells missing values apart with KindInvalid
+	if optional && prefix == "" && !strings.HasPrefix(goType, "[]") && !strings.HasPrefix(goType, "map[") && goType != "xmlrpc.Value" {
+		return "*" + goType
+	}
+
+	return prefix + goType
+}
+
+// paramName converts a param name into an unexported Go identifier, avoiding keywords and names used by generated code.
+func paramName(name string) string {
+	id := goIdentifier(name)
+	r, size := utf8.DecodeRuneInString(id)
+	id = string(unicode.ToLower(r)) + id[size:]
+
+	switch {
+	case token.IsKeyword(id), id == "ctx", id == "c", id == "h", id == "s", id == "args", id == "reply", id == "err":
+		return id + "Arg"
+	default:
+		return id
+	}
+}
@@ -0,0 +1,162 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateService(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "bugzilla.idl.json"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	schema, err := ReadSchema(f)
+	require.NoError(t, err)
+
+	src, err := GenerateService(schema, Config{Package: "bugzilla"})
+	require.NoError(t, err)
+
+	golden := filepath.Join("testdata", "bugzilla.go.golden")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, src, 0o600))
+	}
+
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(src))
+}
+
+func TestGenerateService_NoTime(t *testing.T) {
+	src, err := GenerateService(&Schema{
+		Service: "sample",
+		Methods: []MethodDef{{Name: "sample.ping"}},
+	}, Config{})
+	require.NoError(t, err)
+	require.Contains(t, string(src), "package api\n")
+	require.Contains(t, string(src), "func (c *SampleClient) SamplePing(ctx context.Context) error {")
+	require.Contains(t, string(src), `return c.client.CallContext(ctx, "sample.ping", nil, nil)`)
+	require.NotContains(t, string(src), `"time"`)
+	require.NotContains(t, string(src), "const (")
+}
+
+func TestReadSchema_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		err    string
+	}{
+		{
+			name:   "not json",
+			schema: `service: x`,
+			err:    "cannot read schema",
+		},
+		{
+			name:   "unknown field",
+			schema: `{"service": "x", "method": []}`,
+			err:    `cannot read schema: json: unknown field "method"`,
+		},
+		{
+			name:   "no service",
+			schema: `{"methods": []}`,
+			err:    "invalid schema: service name is not set",
+		},
+		{
+			name:   "duplicate type",
+			schema: `{"service": "x", "types": [{"name": "A", "fields": []}, {"name": "A", "fields": []}]}`,
+			err:    "invalid schema: type 'A' is declared more than once",
+		},
+		{
+			name:   "built-in type",
+			schema: `{"service": "x", "types": [{"name": "string", "fields": []}]}`,
+			err:    "invalid schema: type 'string' conflicts with a built-in type",
+		},
+		{
+			name:   "types named alike in Go",
+			schema: `{"service": "x", "types": [{"name": "bug", "fields": []}, {"name": "Bug", "fields": []}]}`,
+			err:    "invalid schema: types 'bug' and 'Bug' are both named Bug in Go",
+		},
+		{
+			name:   "fields named alike in Go",
+			schema: `{"service": "x", "types": [{"name": "A", "fields": [{"name": "foo_bar", "type": "int"}, {"name": "fooBar", "type": "int"}]}]}`,
+			err:    "invalid schema: type 'A': fields 'foo_bar' and 'fooBar' are both named FooBar in Go",
+		},
+		{
+			name:   "faults named alike in Go",
+			schema: `{"service": "x", "faults": [{"name": "not-found", "code": 1}, {"name": "NotFound", "code": 2}]}`,
+			err:    "invalid schema: faults 'not-found' and 'NotFound' are both named FaultNotFound in Go",
+		},
+		{
+			name:   "params named alike in Go",
+			schema: `{"service": "x", "methods": [{"name": "a", "params": [{"name": "ctx", "type": "int"}, {"name": "ctxArg", "type": "int"}]}]}`,
+			err:    "invalid schema: method 'a': params 'ctx' and 'ctxArg' are both named ctxArg in Go",
+		},
+		{
+			name:   "unknown field type",
+			schema: `{"service": "x", "types": [{"name": "A", "fields": [{"name": "b", "type": "[]B"}]}]}`,
+			err:    "invalid schema: type 'A': field 'b': unknown type '[]B'",
+		},
+		{
+			name:   "duplicate method",
+			schema: `{"service": "x", "methods": [{"name": "a"}, {"name": "a"}]}`,
+			err:    "invalid schema: method 'a' is declared more than once",
+		},
+		{
+			name:   "unknown reply type",
+			schema: `{"service": "x", "methods": [{"name": "a", "returns": "B"}]}`,
+			err:    "invalid schema: method 'a': reply: unknown type 'B'",
+		},
+		{
+			name:   "duplicate param",
+			schema: `{"service": "x", "methods": [{"name": "a", "params": [{"name": "p", "type": "int"}, {"name": "p", "type": "int"}]}]}`,
+			err:    "invalid schema: method 'a': field 'p' is declared more than once",
+		},
+		{
+			name:   "unknown fault",
+			schema: `{"service": "x", "methods": [{"name": "a", "faults": ["Oops"]}]}`,
+			err:    "invalid schema: method 'a': unknown fault 'Oops'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadSchema(strings.NewReader(tt.schema))
+			require.Error(t, err)
+			require.True(t, strings.HasPrefix(err.Error(), tt.err), err.Error())
+		})
+	}
+}
+
+func Test_schemaGoType(t *testing.T) {
+	tests := []struct {
+		ref      string
+		optional bool
+		expected string
+	}{
+		{ref: "int", expected: "int"},
+		{ref: "int", optional: true, expected: "*int"},
+		{ref: "[]dateTime.iso8601", expected: "[]time.Time"},
+		{ref: "[][]Bug", expected: "[][]Bug"},
+		{ref: "[]Bug", optional: true, expected: "[]Bug"},
+		{ref: "bug_info", optional: true, expected: "*BugInfo"},
+		{ref: "struct", optional: true, expected: "map[string]any"},
+		{ref: "base64", optional: true, expected: "[]byte"},
+		{ref: "any", optional: true, expected: "xmlrpc.Value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			require.Equal(t, tt.expected, schemaGoType(tt.ref, tt.optional))
+		})
+	}
+}
+
+func Test_paramName(t *testing.T) {
+	require.Equal(t, "ids", paramName("ids"))
+	require.Equal(t, "bugId", paramName("bug_id"))
+	require.Equal(t, "typeArg", paramName("type"))
+	require.Equal(t, "ctxArg", paramName("ctx"))
+}
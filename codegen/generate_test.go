@@ -0,0 +1,104 @@
+package codegen
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerateClient(t *testing.T) {
+	intro := loadIntrospection(t, "introspection.json")
+
+	src, err := GenerateClient(intro, Config{Package: "sample"})
+	require.NoError(t, err)
+
+	golden := filepath.Join("testdata", "client.go.golden")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, src, 0o600))
+	}
+
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(src))
+}
+
+func TestGenerateClient_Deterministic(t *testing.T) {
+	intro := loadIntrospection(t, "introspection.json")
+	expected, err := GenerateClient(intro, Config{})
+	require.NoError(t, err)
+
+	// Reverse order of methods and signatures, as if reported differently by the server
+	shuffled := loadIntrospection(t, "introspection.json")
+	for i, j := 0, len(shuffled.Methods)-1; i < j; i, j = i+1, j-1 {
+		shuffled.Methods[i], shuffled.Methods[j] = shuffled.Methods[j], shuffled.Methods[i]
+	}
+	for _, m := range shuffled.Methods {
+		for i, j := 0, len(m.Signatures)-1; i < j; i, j = i+1, j-1 {
+			m.Signatures[i], m.Signatures[j] = m.Signatures[j], m.Signatures[i]
+		}
+	}
+
+	src, err := GenerateClient(shuffled, Config{})
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(src))
+}
+
+func TestGenerateClient_Config(t *testing.T) {
+	src, err := GenerateClient(&Introspection{}, Config{Package: "bugzilla", TypeName: "API"})
+	require.NoError(t, err)
+	require.Contains(t, string(src), "package bugzilla\n")
+	require.Contains(t, string(src), "func NewAPI(client *xmlrpc.Client) *API {")
+	require.NotContains(t, string(src), `"time"`)
+}
+
+func Test_goIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "system.listMethods", expected: "SystemListMethods"},
+		{name: "d.multicall2", expected: "DMulticall2"},
+		{name: "Bug.get", expected: "BugGet"},
+		{name: "2fa.check", expected: "X2faCheck"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, goIdentifier(tt.name))
+		})
+	}
+}
+
+func Test_signatureSuffix(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   []string
+		expected string
+	}{
+		{name: "no params", params: nil, expected: "NoArgs"},
+		{name: "ints", params: []string{"int", "i4"}, expected: "IntInt"},
+		{name: "mixed", params: []string{"string", "boolean", "base64", "dateTime.iso8601"}, expected: "StringBoolBytesTime"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, signatureSuffix(tt.params))
+		})
+	}
+}
+
+func loadIntrospection(t *testing.T, name string) *Introspection {
+	f, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	defer f.Close()
+
+	intro, err := ReadIntrospection(f)
+	require.NoError(t, err)
+
+	return intro
+}
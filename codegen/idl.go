@@ -0,0 +1,222 @@
+package codegen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Schema is a declarative description (IDL) of an XML-RPC service, used to generate both client and server code.
+// It is stored as JSON:
+//
+//	{
+//	  "service": "Bugzilla",
+//	  "types": [
+//	    {"name": "Bug", "fields": [{"name": "id", "type": "int"}, {"name": "summary", "type": "string"}]}
+//	  ],
+//	  "faults": [
+//	    {"name": "BugNotFound", "code": 101}
+//	  ],
+//	  "methods": [
+//	    {"name": "Bug.get", "params": [{"name": "ids", "type": "[]int"}], "returns": "[]Bug", "faults": ["BugNotFound"]}
+//	  ]
+//	}
+//
+// Types are referenced by XML-RPC type names (int, i4, double, boolean, string, dateTime.iso8601, base64),
+// names of declared types, "[]" prefix for arrays of a type, "array" and "struct" for untyped containers,
+// and "any" for values of unknown type.
+type Schema struct {
+	// Service is the name of the service, used as a prefix of generated client, handler and registration function.
+	Service string      `json:"service"`
+	Doc     string      `json:"doc,omitempty"`
+	Types   []TypeDef   `json:"types,omitempty"`
+	Faults  []FaultDef  `json:"faults,omitempty"`
+	Methods []MethodDef `json:"methods"`
+}
+
+// TypeDef describes shape of a <struct>.
+type TypeDef struct {
+	Name   string     `json:"name"`
+	Doc    string     `json:"doc,omitempty"`
+	Fields []FieldDef `json:"fields"`
+}
+
+// FieldDef describes a struct member or a method param.
+type FieldDef struct {
+	// Name is the name of the member as sent over the wire. For params it is only used for naming in generated code.
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Optional fields are generated as pointers, so that missing or <nil/> values can be distinguished from zero values.
+	Optional bool   `json:"optional,omitempty"`
+	Doc      string `json:"doc,omitempty"`
+}
+
+// FaultDef describes a fault code returned by the service.
+type FaultDef struct {
+	Name string `json:"name"`
+	Code int    `json:"code"`
+	Doc  string `json:"doc,omitempty"`
+}
+
+// MethodDef describes a remote method.
+type MethodDef struct {
+	Name   string     `json:"name"`
+	Doc    string     `json:"doc,omitempty"`
+	Params []FieldDef `json:"params,omitempty"`
+	// Returns is the type of the reply. Methods without it do not return a value.
+	Returns string `json:"returns,omitempty"`
+	// Faults lists names of faults the method may fail with.
+	Faults []string `json:"faults,omitempty"`
+}
+
+// ReadSchema loads and validates a Schema from JSON.
+func ReadSchema(r io.Reader) (*Schema, error) {
+	schema := &Schema{}
+
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(schema); err != nil {
+		return nil, fmt.Errorf("cannot read schema: %w", err)
+	}
+
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// Validate checks that the schema is complete: names are set and unique, and all referenced types and faults are declared.
+func (s *Schema) Validate() error {
+	if s.Service == "" {
+		return errors.New("invalid schema: service name is not set")
+	}
+
+	types := make(map[string]bool)
+	typeNames := make(map[string]string)
+	for _, t := range s.Types {
+		if t.Name == "" {
+			return errors.New("invalid schema: type without a name")
+		}
+		if goTypes[t.Name] != "" || idlTypes[t.Name] != "" {
+			return fmt.Errorf("invalid schema: type '%s' conflicts with a built-in type", t.Name)
+		}
+		if types[t.Name] {
+			return fmt.Errorf("invalid schema: type '%s' is declared more than once", t.Name)
+		}
+		types[t.Name] = true
+
+		// Names are unique in Go as well, where they are converted to identifiers
+		goName := goIdentifier(t.Name)
+		if other, ok := typeNames[goName]; ok {
+			return fmt.Errorf("invalid schema: types '%s' and '%s' are both named %s in Go", other, t.Name, goName)
+		}
+		typeNames[goName] = t.Name
+	}
+
+	for _, t := range s.Types {
+		if err := validateFields(t.Fields, types); err != nil {
+			return fmt.Errorf("invalid schema: type '%s': %w", t.Name, err)
+		}
+	}
+
+	faults := make(map[string]bool)
+	faultNames := make(map[string]string)
+	for _, f := range s.Faults {
+		if f.Name == "" {
+			return errors.New("invalid schema: fault without a name")
+		}
+		if faults[f.Name] {
+			return fmt.Errorf("invalid schema: fault '%s' is declared more than once", f.Name)
+		}
+		faults[f.Name] = true
+
+		goName := goIdentifier(f.Name)
+		if other, ok := faultNames[goName]; ok {
+			return fmt.Errorf("invalid schema: faults '%s' and '%s' are both named Fault%s in Go", other, f.Name, goName)
+		}
+		faultNames[goName] = f.Name
+	}
+
+	methods := make(map[string]bool)
+	for _, m := range s.Methods {
+		if m.Name == "" {
+			return errors.New("invalid schema: method without a name")
+		}
+		if methods[m.Name] {
+			return fmt.Errorf("invalid schema: method '%s' is declared more than once", m.Name)
+		}
+		methods[m.Name] = true
+
+		if err := validateFields(m.Params, types); err != nil {
+			return fmt.Errorf("invalid schema: method '%s': %w", m.Name, err)
+		}
+		// Params are also arguments of generated methods
+		args := make(map[string]string)
+		for _, p := range m.Params {
+			arg := paramName(p.Name)
+			if other, ok := args[arg]; ok {
+				return fmt.Errorf("invalid schema: method '%s': params '%s' and '%s' are both named %s in Go", m.Name, other, p.Name, arg)
+			}
+			args[arg] = p.Name
+		}
+		if m.Returns != "" {
+			if err := validateTypeRef(m.Returns, types); err != nil {
+				return fmt.Errorf("invalid schema: method '%s': reply: %w", m.Name, err)
+			}
+		}
+		for _, f := range m.Faults {
+			if !faults[f] {
+				return fmt.Errorf("invalid schema: method '%s': unknown fault '%s'", m.Name, f)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateFields(fields []FieldDef, types map[string]bool) error {
+	names := make(map[string]bool)
+	goNames := make(map[string]string)
+	for _, f := range fields {
+		if f.Name == "" {
+			return errors.New("field without a name")
+		}
+		if names[f.Name] {
+			return fmt.Errorf("field '%s' is declared more than once", f.Name)
+		}
+		names[f.Name] = true
+
+		goName := goIdentifier(f.Name)
+		if other, ok := goNames[goName]; ok {
+			return fmt.Errorf("fields '%s' and '%s' are both named %s in Go", other, f.Name, goName)
+		}
+		goNames[goName] = f.Name
+
+		if err := validateTypeRef(f.Type, types); err != nil {
+			return fmt.Errorf("field '%s': %w", f.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func validateTypeRef(ref string, types map[string]bool) error {
+	elem := ref
+	for strings.HasPrefix(elem, "[]") {
+		elem = strings.TrimPrefix(elem, "[]")
+	}
+
+	if goTypes[elem] == "" && idlTypes[elem] == "" && !types[elem] {
+		return fmt.Errorf("unknown type '%s'", ref)
+	}
+
+	return nil
+}
+
+// idlTypes extends goTypes with types only available in schema.
+var idlTypes = map[string]string{
+	"any": "xmlrpc.Value",
+}
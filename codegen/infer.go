@@ -0,0 +1,329 @@
+package codegen
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"alexejk.io/go-xmlrpc"
+)
+
+// InferTypes generates Go type definitions able to decode the params of provided <methodResponse> documents.
+//
+// Shapes of all samples are unified: struct members seen only in some samples (or being <nil/> in some of them)
+// become pointers, while arrays with elements of different types and values of different types across samples become []any or any.
+// Names of fields are converted from member names the same way as during decoding, and nested struct types are named after their members.
+//
+// The root type is named after Config.TypeName, which defaults to "Response" for this function.
+// If all samples have a single param, the root type describes that param, otherwise it is a struct with a field per param.
+// Fault responses are ignored, as they do not describe the reply.
+func InferTypes(samples [][]byte, cfg Config) ([]byte, error) {
+	if cfg.TypeName == "" {
+		cfg.TypeName = "Response"
+	}
+	cfg = cfg.withDefaults()
+
+	var params *shape
+	for i, sample := range samples {
+		response, err := xmlrpc.NewResponse(sample)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse sample %d: %w", i+1, err)
+		}
+		if response.Fault != nil {
+			continue
+		}
+
+		// Params are treated as members of a struct, so missing params are handled the same way as missing members
+		s := &shape{kind: shapeStruct, count: 1}
+		for n, p := range response.Params {
+			s.fields = append(s.fields, &shapeField{
+				name:     "Param" + strconv.Itoa(n+1),
+				typeName: cfg.TypeName + "Param" + strconv.Itoa(n+1),
+				shape:    shapeOf(&p.Value),
+				seen:     1,
+			})
+		}
+		params = unify(params, s)
+	}
+
+	if params == nil {
+		return nil, errors.New("no samples with params provided")
+	}
+
+	g := &inferGenerator{names: newNameSet()}
+	g.names.claim(cfg.TypeName)
+
+	// A single param is described directly, unless it is a struct with a single member,
+	// which would be decoded as a params wrapper
+	root := params
+	if len(params.fields) == 1 && params.fields[0].seen == params.count {
+		if param := params.fields[0].shape; param.kind != shapeStruct || len(param.fields) != 1 {
+			root = param
+		}
+	}
+
+	data := &inferTemplateData{Package: cfg.Package}
+	if root.kind == shapeStruct {
+		g.define(cfg.TypeName, root)
+	} else {
+		g.types = append(g.types, inferType{Name: cfg.TypeName, Underlying: g.goType(root, cfg.TypeName)})
+	}
+
+	data.Types = g.types
+	data.NeedsTime = g.needsTime
+
+	return render(inferTemplate, data)
+}
+
+type shapeKind int
+
+const (
+	shapeUnknown shapeKind = iota // no values seen yet (e.g. elements of an empty array)
+	shapeNil
+	shapeInt
+	shapeDouble
+	shapeBool
+	shapeString
+	shapeBase64
+	shapeTime
+	shapeArray
+	shapeStruct
+	shapeAny
+)
+
+// shape is a unified description of one or more values.
+type shape struct {
+	kind shapeKind
+	// nullable is set when some of the values were <nil/>
+	nullable bool
+	// elem describes all elements of an array
+	elem *shape
+	// fields of a struct, in order of first appearance
+	fields []*shapeField
+	// count is the number of structs unified into the shape, used to detect members missing in some of them
+	count int
+}
+
+type shapeField struct {
+	name string
+	// typeName overrides the name of the struct type defined for the field
+	typeName string
+	shape    *shape
+	seen     int
+}
+
+func shapeOf(v *xmlrpc.ResponseValue) *shape {
+	switch {
+	case v.Nil != nil:
+		return &shape{kind: shapeNil, nullable: true}
+	case v.Int != nil, v.Int4 != nil:
+		return &shape{kind: shapeInt}
+	case v.Double != nil:
+		return &shape{kind: shapeDouble}
+	case v.Boolean != nil:
+		return &shape{kind: shapeBool}
+	case v.Base64 != nil:
+		return &shape{kind: shapeBase64}
+	case v.DateTime != nil:
+		return &shape{kind: shapeTime}
+	case v.Array != nil:
+		var elem *shape
+		for _, item := range v.Array.Values {
+			elem = unify(elem, shapeOf(item))
+		}
+		return &shape{kind: shapeArray, elem: elem}
+	case len(v.Struct) != 0:
+		s := &shape{kind: shapeStruct, count: 1}
+		for _, m := range v.Struct {
+			if f := s.field(m.Name); f != nil {
+				f.shape = unify(f.shape, shapeOf(&m.Value))
+				continue
+			}
+			s.fields = append(s.fields, &shapeField{name: m.Name, shape: shapeOf(&m.Value), seen: 1})
+		}
+		return s
+	default:
+		// Values without a type are strings
+		return &shape{kind: shapeString}
+	}
+}
+
+func (s *shape) field(name string) *shapeField {
+	for _, f := range s.fields {
+		if f.name == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// unify merges two shapes into one describing values of both.
+func unify(a, b *shape) *shape {
+	switch {
+	case a == nil || a.kind == shapeUnknown:
+		return b
+	case b == nil || b.kind == shapeUnknown:
+		return a
+	case a.kind == shapeNil:
+		b.nullable = true
+		return b
+	case b.kind == shapeNil:
+		a.nullable = true
+		return a
+	case a.kind != b.kind:
+		return &shape{kind: shapeAny}
+	}
+
+	a.nullable = a.nullable || b.nullable
+
+	switch a.kind {
+	case shapeArray:
+		a.elem = unify(a.elem, b.elem)
+	case shapeStruct:
+		a.count += b.count
+		for _, bf := range b.fields {
+			if af := a.field(bf.name); af != nil {
+				af.shape = unify(af.shape, bf.shape)
+				af.seen += bf.seen
+			} else {
+				a.fields = append(a.fields, bf)
+			}
+		}
+	}
+
+	return a
+}
+
+type inferGenerator struct {
+	names     nameSet
+	types     []inferType
+	needsTime bool
+}
+
+type inferTemplateData struct {
+	Package   string
+	NeedsTime bool
+	Types     []inferType
+}
+
+type inferType struct {
+	Name string
+	// Underlying is set for non-struct types
+	Underlying string
+	Fields     []inferField
+}
+
+type inferField struct {
+	GoName string
+	// Name is the member name, used for the tag
+	Name string
+	Type string
+}
+
+// define adds a struct type with provided name, describing the shape.
+func (g *inferGenerator) define(name string, s *shape) {
+	idx := len(g.types)
+	g.types = append(g.types, inferType{Name: name})
+
+	var fields []inferField
+	// Members named alike (e.g. "foo_bar" and "fooBar") are distinct fields, decoded by their tags
+	fieldNames := newNameSet()
+	for _, f := range s.fields {
+		field := inferField{GoName: fieldNames.claim(goIdentifier(f.name)), Name: f.name}
+
+		hint := field.GoName
+		if f.typeName != "" {
+			// Params are decoded by position, so their names are not tagged
+			hint, field.Name = f.typeName, ""
+		}
+
+		field.Type = g.goType(f.shape, hint)
+		if (f.seen < s.count || f.shape.nullable) && isPointerable(field.Type) {
+			field.Type = "*" + field.Type
+		}
+
+		fields = append(fields, field)
+	}
+
+	g.types[idx].Fields = fields
+}
+
+// goType returns Go type for the shape, defining struct types named after hint when needed.
+func (g *inferGenerator) goType(s *shape, hint string) string {
+	if s == nil {
+		return "any"
+	}
+
+	switch s.kind {
+	case shapeInt:
+		return "int"
+	case shapeDouble:
+		return "float64"
+	case shapeBool:
+		return "bool"
+	case shapeString:
+		return "string"
+	case shapeBase64:
+		return "[]byte"
+	case shapeTime:
+		g.needsTime = true
+		return timeGoType
+	case shapeArray:
+		if s.elem == nil || s.elem.kind == shapeAny || s.elem.kind == shapeNil {
+			return "[]any"
+		}
+		elem := g.goType(s.elem, singular(hint))
+		if s.elem.nullable && isPointerable(elem) {
+			elem = "*" + elem
+		}
+		return "[]" + elem
+	case shapeStruct:
+		name := g.names.claim(hint)
+		g.define(name, s)
+		return name
+	default:
+		return "any"
+	}
+}
+
+// isPointerable reports whether a field of provided type should become a pointer to tell missing values apart.
+// Slices and interfaces are nil-able already.
+func isPointerable(goType string) bool {
+	return goType != "any" && !strings.HasPrefix(goType, "[]")
+}
+
+// singular derives a name of an array element out of the name of the array, e.g. "Bugs" to "Bug".
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "s") && len(name) > 1 &&
+		!strings.HasSuffix(name, "ss") && !strings.HasSuffix(name, "us") && !strings.HasSuffix(name, "is"):
+		return strings.TrimSuffix(name, "s")
+	default:
+		return name + "Item"
+	}
+}
+
+var inferTemplate = template.Must(template.New("infer").Parse(`// Code generated by xmlrpc-gen from sample responses.
+
+package {{ .Package }}
+{{- if .NeedsTime }}
+
+import "time"
+{{- end }}
+{{ range .Types }}
+{{- if .Underlying }}
+type {{ .Name }} {{ .Underlying }}
+{{ else }}
+type {{ .Name }} struct {
+{{- range .Fields }}
+	{{ .GoName }} {{ .Type }}{{ if .Name }} ` + "`" + `xmlrpc:"{{ .Name }}"` + "`" + `{{ end }}
+{{- end }}
+}
+{{ end }}
+{{- end -}}
+`))
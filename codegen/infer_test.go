@@ -0,0 +1,159 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInferTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		samples  []string
+		expected string
+	}{
+		{
+			name:    "single struct param",
+			samples: []string{"../testdata/response_bugs.xml"},
+			expected: `// Code generated by xmlrpc-gen from sample responses.
+
+package api
+
+import "time"
+
+type Response struct {
+	Bugs   []Bug ` + "`xmlrpc:\"bugs\"`" + `
+	Faults []any ` + "`xmlrpc:\"faults\"`" + `
+}
+
+type Bug struct {
+	Id             int       ` + "`xmlrpc:\"id\"`" + `
+	Summary        string    ` + "`xmlrpc:\"summary\"`" + `
+	IsOpen         bool      ` + "`xmlrpc:\"is_open\"`" + `
+	Score          float64   ` + "`xmlrpc:\"score\"`" + `
+	LastChangeTime time.Time ` + "`xmlrpc:\"last_change_time\"`" + `
+	Status         string    ` + "`xmlrpc:\"status\"`" + `
+}
+`,
+		},
+		{
+			name:    "multiple params",
+			samples: []string{"../testdata/response_simple.xml"},
+			expected: `// Code generated by xmlrpc-gen from sample responses.
+
+package api
+
+type Response struct {
+	Param1 string
+	Param2 int
+}
+`,
+		},
+		{
+			name:    "single struct param with one member",
+			samples: []string{"../testdata/response_bugzilla_version.xml"},
+			expected: `// Code generated by xmlrpc-gen from sample responses.
+
+package api
+
+type Response struct {
+	Param1 ResponseParam1
+}
+
+type ResponseParam1 struct {
+	Version string ` + "`xmlrpc:\"version\"`" + `
+}
+`,
+		},
+		{
+			name:    "mixed array",
+			samples: []string{"../testdata/response_array_mixed.xml"},
+			expected: `// Code generated by xmlrpc-gen from sample responses.
+
+package api
+
+type Response []any
+`,
+		},
+		{
+			name:    "faults are ignored",
+			samples: []string{"../testdata/response_array.xml", "../testdata/response_fault.xml"},
+			expected: `// Code generated by xmlrpc-gen from sample responses.
+
+package api
+
+type Response []int
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := InferTypes(loadSamples(t, tt.samples...), Config{})
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, string(src))
+		})
+	}
+}
+
+func TestInferTypes_Unified(t *testing.T) {
+	src, err := InferTypes(loadSamples(t, "testdata/infer_user_1.xml", "testdata/infer_user_2.xml"), Config{Package: "users", TypeName: "User"})
+	require.NoError(t, err)
+
+	golden := filepath.Join("testdata", "infer_user.go.golden")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, src, 0o600))
+	}
+
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(src))
+}
+
+func TestInferTypes_SimilarMembers(t *testing.T) {
+	sample := `<methodResponse><params><param><value><struct>` +
+		`<member><name>foo_bar</name><value><int>1</int></value></member>` +
+		`<member><name>fooBar</name><value><string>a</string></value></member>` +
+		`</struct></value></param></params></methodResponse>`
+
+	src, err := InferTypes([][]byte{[]byte(sample)}, Config{})
+	require.NoError(t, err)
+	require.Equal(t, `// Code generated by xmlrpc-gen from sample responses.
+
+package api
+
+type Response struct {
+	FooBar  int    `+"`xmlrpc:\"foo_bar\"`"+`
+	FooBar2 string `+"`xmlrpc:\"fooBar\"`"+`
+}
+`, string(src))
+}
+
+func TestInferTypes_Errors(t *testing.T) {
+	_, err := InferTypes([][]byte{[]byte("<methodResponse>")}, Config{})
+	require.ErrorContains(t, err, "cannot parse sample 1")
+
+	_, err = InferTypes(loadSamples(t, "../testdata/response_fault.xml"), Config{})
+	require.EqualError(t, err, "no samples with params provided")
+}
+
+func Test_singular(t *testing.T) {
+	require.Equal(t, "Bug", singular("Bugs"))
+	require.Equal(t, "Entry", singular("Entries"))
+	require.Equal(t, "StatusItem", singular("Status"))
+	require.Equal(t, "AddressItem", singular("Address"))
+	require.Equal(t, "DataItem", singular("Data"))
+}
+
+func loadSamples(t *testing.T, paths ...string) [][]byte {
+	samples := make([][]byte, 0, len(paths))
+	for _, path := range paths {
+		sample, err := os.ReadFile(path)
+		require.NoError(t, err)
+		samples = append(samples, sample)
+	}
+
+	return samples
+}
@@ -0,0 +1,147 @@
+package codegen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"alexejk.io/go-xmlrpc"
+)
+
+// Introspection contains the description of remote methods, as reported by the server introspection API
+// (system.listMethods, system.methodSignature and system.methodHelp).
+// It can be saved as JSON and loaded later, to generate code without access to the server.
+type Introspection struct {
+	Methods []MethodInfo `json:"methods"`
+}
+
+// MethodInfo describes a single remote method.
+type MethodInfo struct {
+	Name string `json:"name"`
+	// Signatures lists supported signatures of the method. First element of each signature is the type of the reply,
+	// followed by types of params. Signatures are empty if server does not report them.
+	Signatures [][]string `json:"signatures,omitempty"`
+	Help       string     `json:"help,omitempty"`
+}
+
+// Introspect queries the server for a list of methods, and their signatures and help texts.
+// Failures to retrieve signatures or help of an individual method are not considered errors, as many servers implement these partially.
+func Introspect(ctx context.Context, client *xmlrpc.Client) (*Introspection, error) {
+	names, err := xmlrpc.Invoke[[]string](ctx, client, "system.listMethods", nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list methods: %w", err)
+	}
+
+	intro := &Introspection{}
+	for _, name := range names {
+		method := MethodInfo{Name: name}
+
+		if sigs, err := xmlrpc.Invoke[xmlrpc.Value](ctx, client, "system.methodSignature", xmlrpc.Args{name}); err == nil {
+			method.Signatures = signaturesFromValue(sigs)
+		} else if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if help, err := xmlrpc.Invoke[string](ctx, client, "system.methodHelp", xmlrpc.Args{name}); err == nil {
+			method.Help = help
+		} else if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		intro.Methods = append(intro.Methods, method)
+	}
+
+	intro.normalize()
+
+	return intro, nil
+}
+
+// ReadIntrospection loads Introspection previously saved with WriteTo.
+func ReadIntrospection(r io.Reader) (*Introspection, error) {
+	intro := &Introspection{}
+	if err := json.NewDecoder(r).Decode(intro); err != nil {
+		return nil, fmt.Errorf("cannot read introspection dump: %w", err)
+	}
+
+	intro.normalize()
+
+	return intro, nil
+}
+
+// WriteTo saves Introspection as indented JSON.
+func (i *Introspection) WriteTo(w io.Writer) (int64, error) {
+	b, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(append(b, '\n'))
+	return int64(n), err
+}
+
+// normalize sorts methods and signatures, so the output does not depend on the order reported by the server.
+// Empty signatures, which lack even the type of the reply, are dropped.
+func (i *Introspection) normalize() {
+	sort.SliceStable(i.Methods, func(a, b int) bool {
+		return i.Methods[a].Name < i.Methods[b].Name
+	})
+
+	for n := range i.Methods {
+		m := &i.Methods[n]
+
+		sigs := m.Signatures[:0]
+		for _, sig := range m.Signatures {
+			if len(sig) != 0 {
+				sigs = append(sigs, sig)
+			}
+		}
+		if len(sigs) == 0 {
+			sigs = nil
+		}
+		m.Signatures = sigs
+
+		sort.SliceStable(m.Signatures, func(a, b int) bool {
+			return signatureKey(m.Signatures[a]) < signatureKey(m.Signatures[b])
+		})
+	}
+}
+
+// signaturesFromValue extracts signatures from the reply of system.methodSignature.
+// Servers that do not know the signature reply with a non-array value (e.g. "undef" string), which results in no signatures.
+// Signatures that are empty or contain anything but type names are skipped, as types would not be aligned with params.
+func signaturesFromValue(v xmlrpc.Value) [][]string {
+	sigValues, err := v.Array()
+	if err != nil {
+		return nil
+	}
+
+	var sigs [][]string
+	for _, sigValue := range sigValues {
+		if sig, ok := signatureFromValue(sigValue); ok {
+			sigs = append(sigs, sig)
+		}
+	}
+
+	return sigs
+}
+
+// signatureFromValue extracts a single signature, reporting false if it is empty or any of its types is not a string.
+func signatureFromValue(v xmlrpc.Value) ([]string, bool) {
+	typeValues, err := v.Array()
+	if err != nil || len(typeValues) == 0 {
+		return nil, false
+	}
+
+	sig := make([]string, 0, len(typeValues))
+	for _, t := range typeValues {
+		name, err := t.Text()
+		if err != nil {
+			return nil, false
+		}
+		sig = append(sig, name)
+	}
+
+	return sig, true
+}
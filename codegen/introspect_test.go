@@ -0,0 +1,128 @@
+package codegen
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"alexejk.io/go-xmlrpc"
+)
+
+func TestIntrospect(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m := &struct {
+			Name   string   `xml:"methodName"`
+			Params []string `xml:"params>param>value>string"`
+		}{}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err, "test server: read body")
+		require.NoError(t, xml.Unmarshal(body, m), "test server: unmarshal body")
+
+		var value string
+		switch {
+		case m.Name == "system.listMethods":
+			value = `<array><data><value><string>sample.add</string></value><value><string>d.multicall2</string></value></data></array>`
+		case m.Name == "system.methodSignature" && m.Params[0] == "sample.add":
+			value = `<array><data>
+				<value><array><data><value><string>int</string></value><value><string>int</string></value><value><string>int</string></value></data></array></value>
+			</data></array>`
+		case m.Name == "system.methodSignature":
+			value = `<string>undef</string>`
+		case m.Name == "system.methodHelp" && m.Params[0] == "sample.add":
+			value = `<string>Adds two numbers.</string>`
+		default:
+			_, _ = fmt.Fprint(w, `<?xml version="1.0"?><methodResponse><fault><value><struct>
+				<member><name>faultCode</name><value><int>-32601</int></value></member>
+				<member><name>faultString</name><value><string>method not found</string></value></member>
+			</struct></value></fault></methodResponse>`)
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, `<?xml version="1.0"?><methodResponse><params><param><value>%s</value></param></params></methodResponse>`, value)
+	}))
+	defer ts.Close()
+
+	c, err := xmlrpc.NewClient(ts.URL)
+	require.NoError(t, err)
+	defer c.Close()
+
+	intro, err := Introspect(context.Background(), c)
+	require.NoError(t, err)
+	require.Equal(t, &Introspection{
+		Methods: []MethodInfo{
+			{Name: "d.multicall2"},
+			{Name: "sample.add", Signatures: [][]string{{"int", "int", "int"}}, Help: "Adds two numbers."},
+		},
+	}, intro)
+}
+
+func TestIntrospection_WriteTo(t *testing.T) {
+	intro := loadIntrospection(t, "introspection.json")
+
+	buf := new(bytes.Buffer)
+	_, err := intro.WriteTo(buf)
+	require.NoError(t, err)
+
+	loaded, err := ReadIntrospection(buf)
+	require.NoError(t, err)
+	require.Equal(t, intro, loaded)
+}
+
+func TestReadIntrospection_EmptySignature(t *testing.T) {
+	intro, err := ReadIntrospection(bytes.NewBufferString(`{"methods":[{"name":"a.b","signatures":[[]]},{"name":"c.d","signatures":[[],["int","string"]]}]}`))
+	require.NoError(t, err)
+	require.Equal(t, &Introspection{
+		Methods: []MethodInfo{
+			{Name: "a.b"},
+			{Name: "c.d", Signatures: [][]string{{"int", "string"}}},
+		},
+	}, intro)
+
+	src, err := GenerateClient(&Introspection{Methods: []MethodInfo{{Name: "a.b", Signatures: [][]string{{}}}}}, Config{})
+	require.NoError(t, err)
+	require.Contains(t, string(src), "func (c *Client) AB(ctx context.Context, args ...any) (xmlrpc.Value, error)")
+}
+
+func Test_signaturesFromValue(t *testing.T) {
+	tests := map[string]struct {
+		value  string
+		expect [][]string
+	}{
+		"signatures": {
+			value:  `<array><data><value><array><data><value>int</value><value><string>string</string></value></data></array></value></data></array>`,
+			expect: [][]string{{"int", "string"}},
+		},
+		"unknown signature": {
+			value: `<string>undef</string>`,
+		},
+		"empty signature": {
+			value:  `<array><data><value><array><data></data></array></value><value><array><data><value>int</value></data></array></value></data></array>`,
+			expect: [][]string{{"int"}},
+		},
+		"non-string type": {
+			value: `<array><data><value><array><data><value><int>1</int></value><value>string</value></data></array></value></data></array>`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var v xmlrpc.Value
+			body := `<methodResponse><params><param><value>` + tt.value + `</value></param></params></methodResponse>`
+			require.NoError(t, (&xmlrpc.StdDecoder{}).DecodeRaw([]byte(body), &v))
+			require.Equal(t, tt.expect, signaturesFromValue(v))
+		})
+	}
+}
+
+func TestReadIntrospection_Invalid(t *testing.T) {
+	_, err := ReadIntrospection(bytes.NewBufferString("not json"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot read introspection dump")
+}
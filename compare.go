@@ -0,0 +1,322 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Difference is a single difference between two documents found by Diff.
+type Difference struct {
+	// Path locates the differing value, using the same notation as Issue.Path.
+	Path string
+	// A and B describe the value in the respective document, e.g. `35 (int)` or `<missing>`.
+	A, B string
+}
+
+func (d Difference) String() string {
+	if d.Path == "" {
+		return fmt.Sprintf("%s != %s", d.A, d.B)
+	}
+	return fmt.Sprintf("%s: %s != %s", d.Path, d.A, d.B)
+}
+
+// CompareOption configures comparison of documents by Equal and Diff.
+type CompareOption func(*comparer)
+
+// TimeTolerance makes <dateTime.iso8601> values equal if they differ by at most d.
+func TimeTolerance(d time.Duration) CompareOption {
+	return func(c *comparer) {
+		c.timeTolerance = d
+	}
+}
+
+// Equal reports whether two <methodCall> or <methodResponse> documents are semantically equal. See Diff for details.
+func Equal(a, b []byte, opts ...CompareOption) (bool, error) {
+	diff, err := Diff(a, b, opts...)
+	if err != nil {
+		return false, err
+	}
+
+	return len(diff) == 0, nil
+}
+
+// Diff compares two <methodCall> or <methodResponse> documents semantically and returns their differences.
+//
+// Formatting and whitespace around non-string values are ignored, as well as order of struct members.
+// Values are compared by their meaning rather than text: <int> and <i4> are the same type, numbers are compared
+// by value (e.g. "007" equals "7"), untyped values equal <string> values, base64 is compared by decoded data and
+// date-times by time instant, optionally with tolerance (see TimeTolerance). Date-times without a time zone are treated as UTC.
+//
+// Error is returned if any of the documents cannot be parsed.
+func Diff(a, b []byte, opts ...CompareOption) ([]Difference, error) {
+	rootA, err := parseNode(a)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse first document: %w", err)
+	}
+	rootB, err := parseNode(b)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse second document: %w", err)
+	}
+
+	c := &comparer{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.document(rootA, rootB)
+
+	return c.diff, nil
+}
+
+// comparer collects differences of compared documents.
+type comparer struct {
+	timeTolerance time.Duration
+
+	diff []Difference
+}
+
+func (c *comparer) report(path, a, b string) {
+	c.diff = append(c.diff, Difference{Path: path, A: a, B: b})
+}
+
+func (c *comparer) document(a, b *node) {
+	if a.name != b.name {
+		c.report("", "<"+a.name+">", "<"+b.name+">")
+		return
+	}
+
+	if nameA, nameB := childText(a, "methodName"), childText(b, "methodName"); nameA != nameB {
+		c.report("methodName", strconv.Quote(nameA), strconv.Quote(nameB))
+	}
+
+	faultA, faultB := firstChild(a, "fault"), firstChild(b, "fault")
+	switch {
+	case faultA != nil && faultB != nil:
+		c.value("fault", firstChild(faultA, "value"), firstChild(faultB, "value"))
+		return
+	case faultA != nil:
+		c.report("", "fault", "params")
+		return
+	case faultB != nil:
+		c.report("", "params", "fault")
+		return
+	}
+
+	paramsA, paramsB := paramValues(a), paramValues(b)
+	for i := 0; i < len(paramsA) || i < len(paramsB); i++ {
+		c.value(fmt.Sprintf("params.%d", i), nodeAt(paramsA, i), nodeAt(paramsB, i))
+	}
+}
+
+// value compares two <value> elements, either of which may be nil if missing.
+func (c *comparer) value(path string, a, b *node) {
+	if a == nil || b == nil {
+		if a != b {
+			c.report(path, describe(a), describe(b))
+		}
+		return
+	}
+
+	typA, textA := valueTypeOf(a)
+	typB, textB := valueTypeOf(b)
+	if typA != typB {
+		c.report(path, describe(a), describe(b))
+		return
+	}
+
+	switch typA {
+	case "array":
+		itemsA, itemsB := arrayValues(a), arrayValues(b)
+		for i := 0; i < len(itemsA) || i < len(itemsB); i++ {
+			c.value(joinPath(path, strconv.Itoa(i)), nodeAt(itemsA, i), nodeAt(itemsB, i))
+		}
+
+	case "struct":
+		membersA, namesA := structMembers(a)
+		membersB, namesB := structMembers(b)
+		for _, name := range namesA {
+			c.value(joinPath(path, name), membersA[name], membersB[name])
+		}
+		for _, name := range namesB {
+			if _, ok := membersA[name]; !ok {
+				c.value(joinPath(path, name), nil, membersB[name])
+			}
+		}
+
+	default:
+		if !c.scalarEqual(typA, textA, textB) {
+			c.report(path, describe(a), describe(b))
+		}
+	}
+}
+
+func (c *comparer) scalarEqual(typ, a, b string) bool {
+	if typ == "string" {
+		return a == b
+	}
+
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	switch typ {
+	case "int":
+		ia, errA := strconv.ParseInt(a, 10, 64)
+		ib, errB := strconv.ParseInt(b, 10, 64)
+		if errA == nil && errB == nil {
+			return ia == ib
+		}
+
+	case "double":
+		fa, errA := strconv.ParseFloat(a, 64)
+		fb, errB := strconv.ParseFloat(b, 64)
+		if errA == nil && errB == nil {
+			return fa == fb
+		}
+
+	case "boolean":
+		ba, errA := (&StdDecoder{}).decodeBoolean(a)
+		bb, errB := (&StdDecoder{}).decodeBoolean(b)
+		if errA == nil && errB == nil {
+			return ba == bb
+		}
+
+	case "base64":
+		da, errA := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(a), ""))
+		db, errB := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(b), ""))
+		if errA == nil && errB == nil {
+			return bytes.Equal(da, db)
+		}
+
+	case "dateTime.iso8601":
+		ta, errA := parseDateTime(a)
+		tb, errB := parseDateTime(b)
+		if errA == nil && errB == nil {
+			d := ta.Sub(tb)
+			return d <= c.timeTolerance && -d <= c.timeTolerance
+		}
+	}
+
+	// Values which cannot be parsed are compared as text
+	return a == b
+}
+
+// valueTypeOf returns normalized type of a <value> element and its text.
+// Untyped values are strings, <i4> is the same as <int>, and elements of unknown types are compared in canonical form.
+func valueTypeOf(v *node) (string, string) {
+	if len(v.children) == 0 {
+		return "string", v.text
+	}
+
+	t := v.children[0]
+	switch {
+	case t.name == "i4":
+		return "int", t.text
+	case scalarTypes[t.name]:
+		return t.name, t.text
+	case t.name == "array", t.name == "struct", t.name == "nil":
+		return t.name, ""
+	default:
+		buf := new(bytes.Buffer)
+		_ = t.format(buf, 0)
+		return t.name, buf.String()
+	}
+}
+
+// describe returns a short description of a <value> element, used in differences.
+func describe(v *node) string {
+	if v == nil {
+		return "<missing>"
+	}
+
+	typ, text := valueTypeOf(v)
+	switch typ {
+	case "nil":
+		return "nil"
+	case "array":
+		return fmt.Sprintf("array of %d values", len(arrayValues(v)))
+	case "struct":
+		_, names := structMembers(v)
+		return fmt.Sprintf("struct of %d members", len(names))
+	case "string":
+		return strconv.Quote(text) + " (string)"
+	default:
+		return strings.TrimSpace(text) + " (" + typ + ")"
+	}
+}
+
+func parseDateTime(text string) (time.Time, error) {
+	var err error
+	for _, layout := range dateTimeLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, text); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, err
+}
+
+// paramValues returns <value> elements of all params of a document.
+func paramValues(doc *node) []*node {
+	var values []*node
+	for _, params := range doc.childrenNamed("params") {
+		for _, p := range params.childrenNamed("param") {
+			values = append(values, firstChild(p, "value"))
+		}
+	}
+
+	return values
+}
+
+// arrayValues returns <value> elements of an array value.
+func arrayValues(v *node) []*node {
+	var values []*node
+	for _, data := range v.children[0].childrenNamed("data") {
+		values = append(values, data.childrenNamed("value")...)
+	}
+
+	return values
+}
+
+// structMembers returns <value> elements of a struct value by member name, and member names in order of appearance.
+func structMembers(v *node) (map[string]*node, []string) {
+	members := make(map[string]*node)
+	var names []string
+	for _, m := range v.children[0].childrenNamed("member") {
+		name := childText(m, "name")
+		if _, ok := members[name]; ok {
+			continue
+		}
+		members[name] = firstChild(m, "value")
+		names = append(names, name)
+	}
+
+	return members, names
+}
+
+func firstChild(n *node, name string) *node {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func childText(n *node, name string) string {
+	if c := firstChild(n, name); c != nil {
+		return strings.TrimSpace(c.text)
+	}
+
+	return ""
+}
+
+func nodeAt(nodes []*node, i int) *node {
+	if i < len(nodes) {
+		return nodes[i]
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package xmlrpc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	const response = `<methodResponse><params><param>%s</param></params></methodResponse>`
+	wrap := func(value string) []byte {
+		return []byte(strings.Replace(response, "%s", value, 1))
+	}
+
+	tests := []struct {
+		name string
+		a, b []byte
+		opts []CompareOption
+		diff []string
+	}{
+		{
+			name: "formatting and member order",
+			a:    loadTestFile(t, "response_struct.xml"),
+			b:    loadTestFile(t, "response_struct_formatted.xml"),
+		},
+		{
+			name: "normalized scalars",
+			a:    wrap(`<value><array><data><value><i4>007</i4></value><value><double>1.50</double></value><value><boolean>1</boolean></value><value>text</value><value><base64>aGVs bG8=</base64></value><value><dateTime.iso8601>20240102T03:04:05</dateTime.iso8601></value></data></array></value>`),
+			b:    wrap(`<value><array><data><value><int> 7 </int></value><value><double>1.5</double></value><value><boolean>true</boolean></value><value><string>text</string></value><value><base64>aGVsbG8=</base64></value><value><dateTime.iso8601>2024-01-02T05:04:05+02:00</dateTime.iso8601></value></data></array></value>`),
+		},
+		{
+			name: "string whitespace is significant",
+			a:    wrap(`<value><string>a</string></value>`),
+			b:    wrap(`<value><string> a</string></value>`),
+			diff: []string{`params.0: "a" (string) != " a" (string)`},
+		},
+		{
+			name: "different types",
+			a:    wrap(`<value><int>1</int></value>`),
+			b:    wrap(`<value><double>1</double></value>`),
+			diff: []string{"params.0: 1 (int) != 1 (double)"},
+		},
+		{
+			name: "struct members",
+			a:    wrap(`<value><struct><member><name>a</name><value><int>1</int></value></member><member><name>b</name><value><nil/></value></member></struct></value>`),
+			b:    wrap(`<value><struct><member><name>c</name><value><struct></struct></value></member><member><name>a</name><value><int>2</int></value></member></struct></value>`),
+			diff: []string{
+				"params.0.a: 1 (int) != 2 (int)",
+				"params.0.b: nil != <missing>",
+				"params.0.c: <missing> != struct of 0 members",
+			},
+		},
+		{
+			name: "array elements",
+			a:    wrap(`<value><array><data><value>a</value><value><array><data/></array></value></data></array></value>`),
+			b:    wrap(`<value><array><data><value>a</value><value><array><data><value>b</value></data></array></value><value>c</value></data></array></value>`),
+			diff: []string{
+				`params.0.1.0: <missing> != "b" (string)`,
+				`params.0.2: <missing> != "c" (string)`,
+			},
+		},
+		{
+			name: "time tolerance",
+			a:    wrap(`<value><dateTime.iso8601>20240102T03:04:05</dateTime.iso8601></value>`),
+			b:    wrap(`<value><dateTime.iso8601>20240102T03:04:07</dateTime.iso8601></value>`),
+			opts: []CompareOption{TimeTolerance(2 * time.Second)},
+		},
+		{
+			name: "time out of tolerance",
+			a:    wrap(`<value><dateTime.iso8601>20240102T03:04:05</dateTime.iso8601></value>`),
+			b:    wrap(`<value><dateTime.iso8601>20240102T03:04:07</dateTime.iso8601></value>`),
+			opts: []CompareOption{TimeTolerance(time.Second)},
+			diff: []string{"params.0: 20240102T03:04:05 (dateTime.iso8601) != 20240102T03:04:07 (dateTime.iso8601)"},
+		},
+		{
+			name: "unparsable values are compared as text",
+			a:    wrap(`<value><int>one</int></value>`),
+			b:    wrap(`<value><int> one </int></value>`),
+		},
+		{
+			name: "fault and params",
+			a:    loadTestFile(t, "response_fault.xml"),
+			b:    loadTestFile(t, "response_simple.xml"),
+			diff: []string{"fault != params"},
+		},
+		{
+			name: "params count",
+			a:    loadTestFile(t, "response_simple.xml"),
+			b:    wrap(`<value><string>Hello</string></value>`),
+			diff: []string{`params.0: "South Dakota" (string) != "Hello" (string)`, `params.1: 12345 (int) != <missing>`},
+		},
+		{
+			name: "method calls",
+			a:    []byte(`<methodCall><methodName>a</methodName><params><param><value><i4>1</i4></value></param></params></methodCall>`),
+			b:    []byte(`<methodCall><methodName> b </methodName></methodCall>`),
+			diff: []string{`methodName: "a" != "b"`, "params.0: 1 (int) != <missing>"},
+		},
+		{
+			name: "call and response",
+			a:    []byte(`<methodCall><methodName>a</methodName></methodCall>`),
+			b:    wrap(`<value>a</value>`),
+			diff: []string{"<methodCall> != <methodResponse>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := Diff(tt.a, tt.b, tt.opts...)
+			require.NoError(t, err)
+
+			var actual []string
+			for _, d := range diff {
+				actual = append(actual, d.String())
+			}
+			require.Equal(t, tt.diff, actual)
+
+			equal, err := Equal(tt.a, tt.b, tt.opts...)
+			require.NoError(t, err)
+			require.Equal(t, len(tt.diff) == 0, equal)
+		})
+	}
+}
+
+func TestDiff_Errors(t *testing.T) {
+	_, err := Diff([]byte("<html/>"), []byte("<methodCall/>"))
+	require.EqualError(t, err, "cannot parse first document: unexpected root element <html>, expected <methodCall> or <methodResponse>")
+
+	_, err = Equal([]byte("<methodCall/>"), []byte(""))
+	require.EqualError(t, err, "cannot parse second document: document has no root element")
+}
+
+func TestDiff_EncodedMap(t *testing.T) {
+	// Members of maps are encoded in random order
+	body := new(strings.Builder)
+	require.NoError(t, (&StdEncoder{}).Encode(body, "d.multicall", map[string]any{"a": 1, "b": "x", "c": true, "d": 1.5}))
+
+	equal, err := Equal([]byte(body.String()), []byte(`<methodCall><methodName>d.multicall</methodName><params><param><value><struct>
+		<member><name>d</name><value><double>1.5</double></value></member>
+		<member><name>c</name><value><boolean>1</boolean></value></member>
+		<member><name>b</name><value><string>x</string></value></member>
+		<member><name>a</name><value><int>1</int></value></member>
+	</struct></value></param></params></methodCall>`))
+	require.NoError(t, err)
+	require.True(t, equal)
+}
@@ -3,10 +3,13 @@ package xmlrpc
 import (
 	"encoding/base64"
 	"fmt"
+	"io"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
+
+	"alexejk.io/go-xmlrpc/internal/naming"
 )
 
 const (
@@ -26,9 +29,26 @@ type Decoder interface {
 // StdDecoder is the default implementation of the Decoder interface.
 type StdDecoder struct {
 	skipUnknownFields bool
+	lenientParams     bool
+	progress          ProgressFunc
+	sinks             map[reflect.Type]func(field reflect.Value) io.Writer
+	// repair mode, enabled by a callback reporting repairs of responses
+	repair          func(Repair)
+	fallbackCharset string
+	dialect         DialectProfile
 }
 
+var (
+	timeType           = reflect.TypeOf(time.Time{})
+	paramListAnyType   = reflect.TypeOf([]any{})
+	paramListValueType = reflect.TypeOf([]Value{})
+)
+
 func (d *StdDecoder) DecodeRaw(body []byte, v interface{}) error {
+	if d.repair != nil {
+		body = d.repairBody(body)
+	}
+
 	response, err := NewResponse(body)
 	if err != nil {
 		return err
@@ -41,17 +61,83 @@ func (d *StdDecoder) DecodeRaw(body []byte, v interface{}) error {
 	return d.Decode(response, v)
 }
 
+// Decode decodes response params into v, which must be a pointer to one of the following:
+//
+//   - a struct with an exported field per param, decoded in the order fields are defined on the type
+//     (other structs, e.g. big.Int without exported fields, tuples, Value or time.Time, receive a single param);
+//   - a []any or []Value, receiving any number of params;
+//   - any other type, receiving the value of a single param (including a struct, when the response consists of a single <struct> or <array> param).
+//
+// Unless lenient params mode is enabled, the number of params must match the expectation of the target.
+// In lenient mode, extra params are ignored and missing ones leave corresponding fields untouched.
 func (d *StdDecoder) Decode(response *Response, v interface{}) error {
-	// Validate that v has same number of public fields as response params
-	if err := fieldsMustEqual(v, len(response.Params)); err != nil {
-		return err
+	if rv := reflect.ValueOf(v); rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer, got %T", v)
 	}
+	vElem := reflect.Indirect(reflect.ValueOf(v))
 
+	switch {
+	case isParamsStruct(vElem.Type()):
+		return d.decodeParamsStruct(response.Params, v)
+
+	case vElem.Type() == paramListAnyType || vElem.Type() == paramListValueType:
+		slice := reflect.MakeSlice(vElem.Type(), len(response.Params), len(response.Params))
+		for i, param := range response.Params {
+			if err := d.decodeValue(&param.Value, slice.Index(i)); err != nil {
+				return fmt.Errorf("failed decoding param at index %d: %w", i, err)
+			}
+		}
+		vElem.Set(slice)
+
+		return nil
+
+	default:
+		if len(response.Params) != 1 && !d.lenientParams {
+			return fmt.Errorf("number of params (%d) doesnt match expectation (1) of response type %T", len(response.Params), v)
+		}
+
+		if len(response.Params) == 0 {
+			return nil
+		}
+
+		return d.decodeValue(&response.Params[0].Value, vElem)
+	}
+}
+
+// isParamsStruct reports whether params are decoded into a struct of type t by field position, which is the case for
+// structs with exported fields (or none at all), other than tuples and types decoded from a single value.
+func isParamsStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == valueType || t == timeType || isTuple(t) {
+		return false
+	}
+
+	return t.NumField() == 0 || len(planOf(t).exported) != 0
+}
+
+// decodeParamsStruct decodes params into a struct by field position.
+// If the struct does not have a field per param, a single <struct> or <array> param may be decoded into the struct itself
+// (see decodesWhole).
+func (d *StdDecoder) decodeParamsStruct(params []*ResponseParam, v interface{}) error {
 	vElem := reflect.Indirect(reflect.ValueOf(v))
-	for i, param := range response.Params {
-		field := vElem.Field(i)
+	fields := exportedFields(vElem)
 
-		if err := d.decodeValue(&param.Value, field); err != nil {
+	// Validate that v has same number of public fields as response params
+	if err := fieldsMustEqual(v, len(params)); err != nil {
+		if len(params) == 1 && d.decodesWhole(&params[0].Value, fields) {
+			return d.decodeValue(&params[0].Value, vElem)
+		}
+
+		if !d.lenientParams {
+			return err
+		}
+	}
+
+	for i, param := range params {
+		if i >= len(fields) {
+			break
+		}
+
+		if err := d.decodeValue(&param.Value, fields[i]); err != nil {
 			return err
 		}
 	}
@@ -59,6 +145,21 @@ func (d *StdDecoder) Decode(response *Response, v interface{}) error {
 	return nil
 }
 
+// decodesWhole reports whether a single param, while the struct does not have a field per param, is decoded into the struct itself.
+// This is the case for <struct> and <array> params, unless params are lenient and the first field can receive the param,
+// as the remaining params are then treated as missing.
+func (d *StdDecoder) decodesWhole(value *ResponseValue, fields []reflect.Value) bool {
+	kind := "struct"
+	switch {
+	case value.Array != nil:
+		kind = "array"
+	case len(value.Struct) == 0 && !isEmptyStruct(value):
+		return false
+	}
+
+	return !d.lenientParams || len(fields) == 0 || !acceptsContainer(fields[0].Type(), kind)
+}
+
 func (d *StdDecoder) DecodeFault(response *Response) *Fault {
 	if response.Fault == nil {
 		return nil
@@ -90,8 +191,48 @@ func (d *StdDecoder) decodeFault(fault *ResponseFault) *Fault {
 }
 
 func (d *StdDecoder) decodeValue(value *ResponseValue, field reflect.Value) error {
+	// <nil/> resets the field to its zero value (nil for pointers, slices and maps)
+	if value.Nil != nil && field.Type() != valueType && field.Type() != rawValueType {
+		for !field.CanSet() && field.Kind() == reflect.Ptr && !field.IsNil() {
+			field = field.Elem()
+		}
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	// Writers and registered sinks receive decoded data instead of being assigned
+	if w, ok := d.sinkOf(field, value.Base64 != nil); ok {
+		sink := d.newBase64Sink(w)
+		if _, err := io.WriteString(sink, *value.Base64); err != nil {
+			return err
+		}
+		return sink.Close()
+	}
+
 	field = indirect(field)
 
+	// Dynamic values retain the original data type and are built directly out of the response value
+	if field.Type() == valueType {
+		field.Set(reflect.ValueOf(newValue(value)))
+		return nil
+	}
+
+	// Raw values are kept as-is, to be decoded later
+	if field.Type() == rawValueType {
+		field.SetBytes([]byte(value.RawXML))
+		return nil
+	}
+
+	// Ordered structs keep members in the order of the response
+	if field.Type() == orderedStructType {
+		s, err := d.decodeOrderedStruct(value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(s))
+		return nil
+	}
+
 	var val interface{}
 	var err error
 
@@ -117,6 +258,9 @@ func (d *StdDecoder) decodeValue(value *ResponseValue, field reflect.Value) erro
 	case value.DateTime != nil:
 		val, err = d.decodeDateTime(*value.DateTime)
 
+	case d.dialect.AcceptExtensions && value.isExtension():
+		val, err = d.decodeExtension(value, field)
+
 	// Array decoding
 	case value.Array != nil:
 		fieldKind := field.Kind()
@@ -130,6 +274,14 @@ func (d *StdDecoder) decodeValue(value *ResponseValue, field reflect.Value) erro
 			field.Set(reflect.MakeSlice(fieldType, 0, 0))
 		}
 
+		// Positional decoding into tuple structs and fixed-size arrays
+		if (fieldKind == reflect.Struct && isTuple(field.Type())) || fieldKind == reflect.Array {
+			if err := d.decodeTuple(value.Array.Values, field); err != nil {
+				return err
+			}
+			break
+		}
+
 		if fieldKind != reflect.Slice {
 			return fmt.Errorf(errFormatInvalidFieldType, reflect.Slice.String(), field.Kind().String())
 		}
@@ -150,8 +302,8 @@ func (d *StdDecoder) decodeValue(value *ResponseValue, field reflect.Value) erro
 
 		val = slice.Interface()
 
-	// Struct decoding
-	case len(value.Struct) != 0:
+	// Struct decoding, empty structs are told apart from untyped values by the raw XML
+	case len(value.Struct) != 0 || isEmptyStruct(value):
 		fieldKind := field.Kind()
 		fieldType := field.Type()
 
@@ -190,15 +342,12 @@ func (d *StdDecoder) decodeValue(value *ResponseValue, field reflect.Value) erro
 
 				field.SetMapIndex(mapKey, f)
 			} else {
-				// Upper-case the name
-				fName := structMemberToFieldName(m.Name)
-				f := findFieldByNameOrTag(field, fName)
-
+				f := planOf(fieldType).field(field, m.Name)
 				if !f.IsValid() {
 					if d.skipUnknownFields {
 						continue
 					}
-					return fmt.Errorf("cannot find field '%s' on struct", fName)
+					return fmt.Errorf("cannot find field '%s' on struct", structMemberToFieldName(m.Name))
 				}
 
 				if err := d.decodeValue(&m.Value, f); err != nil {
@@ -209,7 +358,7 @@ func (d *StdDecoder) decodeValue(value *ResponseValue, field reflect.Value) erro
 
 	default:
 		// Default to inner string (raw XML)
-		val, err = value.RawXML, nil
+		val, err = d.decodeUntyped(value.RawXML)
 	}
 
 	if err != nil {
@@ -217,16 +366,56 @@ func (d *StdDecoder) decodeValue(value *ResponseValue, field reflect.Value) erro
 	}
 
 	if val != nil {
-		// Assign if directly assignable, or convert to type if convertible
-		rVal := reflect.ValueOf(val)
-		if rVal.Type().AssignableTo(field.Type()) {
-			field.Set(rVal)
-		} else {
-			if !rVal.Type().ConvertibleTo(field.Type()) {
-				return fmt.Errorf("type '%s' cannot be assigned a value of type '%s'", field.Type().String(), rVal.Type().String())
-			}
+		if field.Kind() == reflect.Bool {
+			val = d.lenientBoolean(val)
+		}
+		return assignValue(field, val)
+	}
+
+	return nil
+}
+
+// assignValue sets a decoded value to the field if directly assignable, or converts it to the field type if convertible.
+func assignValue(field reflect.Value, val interface{}) error {
+	rVal := reflect.ValueOf(val)
+	if rVal.Type().AssignableTo(field.Type()) {
+		field.Set(rVal)
+		return nil
+	}
+
+	if !rVal.Type().ConvertibleTo(field.Type()) {
+		return fmt.Errorf("type '%s' cannot be assigned a value of type '%s'", field.Type().String(), rVal.Type().String())
+	}
+
+	field.Set(rVal.Convert(field.Type()))
+
+	return nil
+}
 
-			field.Set(rVal.Convert(field.Type()))
+// decodeTuple assigns array values to a tuple struct or a fixed-size array by position.
+// Elements missing at the end of the array leave remaining fields untouched, while extra elements
+// are only allowed when unknown fields are skipped.
+func (d *StdDecoder) decodeTuple(values []*ResponseValue, field reflect.Value) error {
+	var targets []reflect.Value
+	if field.Kind() == reflect.Array {
+		for i := 0; i < field.Len(); i++ {
+			targets = append(targets, field.Index(i))
+		}
+	} else {
+		targets = exportedFields(field)
+	}
+
+	if len(values) > len(targets) && !d.skipUnknownFields {
+		return fmt.Errorf("cannot decode array of %d values into '%s' of %d elements", len(values), field.Type().String(), len(targets))
+	}
+
+	for i, v := range values {
+		if i >= len(targets) {
+			break
+		}
+
+		if err := d.decodeValue(v, targets[i]); err != nil {
+			return fmt.Errorf("failed decoding array item at index %d: %w", i, err)
 		}
 	}
 
@@ -271,9 +460,18 @@ func (d *StdDecoder) decodeDateTime(value string) (time.Time, error) {
 	if value == "" {
 		return time.Time{}, nil
 	}
+
+	// Layout of the dialect is accepted as well
+	if layout := d.dialect.timeLayout(); layout != time.RFC3339 {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
 	return time.Parse(time.RFC3339, value)
 }
 
+// findFieldByNameOrTag returns the field named fName, preferring fields whose `xmlrpc` tag names them so.
+// Decoding resolves fields through structPlan, which caches the outcome of this lookup per type.
 func findFieldByNameOrTag(field reflect.Value, fName string) reflect.Value {
 	typ := field.Type()
 	for i := 0; i < typ.NumField(); i++ {
@@ -322,13 +520,7 @@ func getFieldNameFromTag(f *reflect.StructField, tagName string) string {
 
 func fieldsMustEqual(v interface{}, expectation int) error {
 	vElem := reflect.Indirect(reflect.ValueOf(v))
-	numFields := 0
-
-	for i := 0; i < vElem.NumField(); i++ {
-		if vElem.Field(i).CanInterface() {
-			numFields++
-		}
-	}
+	numFields := len(planOf(vElem.Type()).exported)
 
 	if numFields != expectation {
 		return fmt.Errorf("number of exported fields (%d) on response type doesnt match expectation (%d)", numFields, expectation)
@@ -337,34 +529,20 @@ func fieldsMustEqual(v interface{}, expectation int) error {
 	return nil
 }
 
-func structMemberToFieldName(structName string) string {
-	b := new(strings.Builder)
-	capNext := true
-
-	for _, v := range structName {
-		if v >= 'A' && v <= 'Z' {
-			b.WriteRune(v)
-		}
-		if v >= '0' && v <= '9' {
-			b.WriteRune(v)
-		}
-
-		if v >= 'a' && v <= 'z' {
-			if capNext {
-				b.WriteString(strings.ToUpper(string(v)))
-			} else {
-				b.WriteRune(v)
-			}
-		}
-
-		if v == '_' || v == ' ' || v == '-' || v == '.' {
-			capNext = true
-		} else {
-			capNext = false
-		}
+// exportedFields returns a list of exported fields on a struct value, in the order they are defined on the type.
+func exportedFields(v reflect.Value) []reflect.Value {
+	plan := planOf(v.Type())
+	fields := make([]reflect.Value, len(plan.exported))
+	for i, index := range plan.exported {
+		fields[i] = v.Field(index)
 	}
 
-	return b.String()
+	return fields
+}
+
+// structMemberToFieldName returns the name of a Go struct field that a <struct> member is decoded into.
+func structMemberToFieldName(structName string) string {
+	return naming.FieldName(structName)
 }
 
 // indirect walks down v allocating pointers as needed,
@@ -44,6 +44,18 @@ type ResponseValue struct {
 	Boolean  *string                 `xml:"boolean"`
 	DateTime *string                 `xml:"dateTime.iso8601"`
 	Base64   *string                 `xml:"base64"`
+	Nil      *struct{}               `xml:"nil"`
+
+	// Vendor extension types (e.g. <ex:i8> of Apache ws-xmlrpc), decoded if the dialect accepts extensions
+	Int1         *string      `xml:"i1"`
+	Int2         *string      `xml:"i2"`
+	Int8         *string      `xml:"i8"`
+	Float        *string      `xml:"float"`
+	BigInteger   *string      `xml:"biginteger"`
+	BigDecimal   *string      `xml:"bigdecimal"`
+	ExtDateTime  *string      `xml:"dateTime"`
+	DOM          *ResponseDOM `xml:"dom"`
+	Serializable *string      `xml:"serializable"`
 
 	RawXML string `xml:",innerxml"`
 }
@@ -0,0 +1,981 @@
+package xmlrpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// streamedTypes are value types decoded directly from tokens. Values of other types are read into a ResponseValue first.
+var streamedTypes = map[string]bool{
+	"i4":               true,
+	"int":              true,
+	"double":           true,
+	"boolean":          true,
+	"string":           true,
+	"base64":           true,
+	"dateTime.iso8601": true,
+	"array":            true,
+	"struct":           true,
+	"nil":              true,
+}
+
+// DecodeStream decodes a response read from r into v, following the same rules as Decode. Fault responses are returned as *Fault.
+//
+// Unless repair mode is enabled, the response is not read into memory and unmarshalled into a Response first: values are decoded from XML tokens
+// directly into v as they are read. Only values decoded into dynamic targets (any, Value, RawValue, OrderedStruct,
+// tuples and fixed-size arrays) are read into an intermediate tree, one value at a time.
+//
+// Resource limits of Client are not applied: r is read until the response ends, and repair mode reads all of it into memory.
+// Responses of untrusted origin must be bounded by r (e.g. with io.LimitReader or http.MaxBytesReader).
+func (d *StdDecoder) DecodeStream(r io.Reader, v interface{}) error {
+	// Repairs need the whole body, bounded only by r
+	if d.repair != nil {
+		body, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(d.repairBody(body))
+	}
+
+	stream := d.newResponseStream(r, nil)
+
+	fault, err := stream.readHeader()
+	if err != nil {
+		return err
+	}
+	if fault != nil {
+		return fault
+	}
+
+	return stream.decodeParams(v)
+}
+
+// responseStream decodes a response from XML tokens. It is positioned by readHeader at the response params, which are then
+// decoded by decodeParams.
+type responseStream struct {
+	decoder *StdDecoder
+	dec     *xml.Decoder
+	rec     *recorder
+	scanner *limitScanner
+
+	// offset of the last token read
+	offset int64
+	// set while positioned within a <param>, or after the last param
+	inParam, done bool
+}
+
+func (d *StdDecoder) newResponseStream(r io.Reader, l *limits) *responseStream {
+	rec := &recorder{r: bufio.NewReader(r)}
+
+	dec := xml.NewDecoder(rec)
+	dec.CharsetReader = func(label string, _ io.Reader) (io.Reader, error) {
+		// Keep reading through the recorder, so that recorded contents are decoded text
+		converted, err := charset.NewReaderLabel(label, rec.r)
+		if err != nil {
+			return nil, err
+		}
+		rec.r = bufio.NewReader(converted)
+
+		return rec, nil
+	}
+
+	s := &responseStream{decoder: d, dec: dec, rec: rec}
+	if l != nil && l.hasStructural() {
+		s.scanner = &limitScanner{limits: l}
+	}
+
+	return s
+}
+
+// recorder is the reader of a token decoder, keeping bytes read while recording.
+// As it is an io.ByteReader, xml.Decoder reads it byte by byte without buffering of its own,
+// so input offsets reported by the decoder match recorded bytes.
+type recorder struct {
+	r         *bufio.Reader
+	recording bool
+	buf       []byte
+}
+
+func (r *recorder) ReadByte() (byte, error) {
+	b, err := r.r.ReadByte()
+	if err == nil && r.recording {
+		r.buf = append(r.buf, b)
+	}
+
+	return b, err
+}
+
+func (r *recorder) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if r.recording {
+		r.buf = append(r.buf, p[:n]...)
+	}
+
+	return n, err
+}
+
+func (r *recorder) start() {
+	r.recording = true
+	r.buf = r.buf[:0]
+}
+
+func (r *recorder) stop() {
+	r.recording = false
+}
+
+// next reads the next token, checking it against limits.
+func (s *responseStream) next() (xml.Token, error) {
+	s.offset = s.dec.InputOffset()
+
+	token, err := s.dec.Token()
+	if errors.Is(err, io.EOF) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.scanner != nil {
+		if err := s.scanner.token(token); err != nil {
+			return nil, err
+		}
+	}
+
+	return token, nil
+}
+
+// skipElement reads the rest of an element, whose start was just read.
+func (s *responseStream) skipElement() error {
+	for depth := 1; depth > 0; {
+		token, err := s.next()
+		if err != nil {
+			return err
+		}
+
+		switch token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	return nil
+}
+
+// readText reads text of an element, whose start was just read, up to its end.
+func (s *responseStream) readText() (string, error) {
+	text := new(strings.Builder)
+	for depth := 0; ; {
+		token, err := s.next()
+		if err != nil {
+			return "", err
+		}
+
+		switch t := token.(type) {
+		case xml.CharData:
+			if depth == 0 {
+				text.Write(t)
+			}
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				return text.String(), nil
+			}
+			depth--
+		}
+	}
+}
+
+// decodeBase64To decodes text of a <base64> element, whose start was just read, into w as it is read.
+func (s *responseStream) decodeBase64To(w io.Writer) error {
+	sink := s.decoder.newBase64Sink(w)
+	for depth := 0; ; {
+		token, err := s.next()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.CharData:
+			if depth == 0 {
+				if _, err := sink.Write(t); err != nil {
+					return err
+				}
+			}
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				return sink.Close()
+			}
+			depth--
+		}
+	}
+}
+
+// readHeader reads the response up to its params, or returns its fault.
+func (s *responseStream) readHeader() (*Fault, error) {
+	// Any root element is accepted, as by NewResponse
+	for root := false; !root; {
+		token, err := s.next()
+		if err != nil {
+			return nil, err
+		}
+		_, root = token.(xml.StartElement)
+	}
+
+	for {
+		token, err := s.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "params":
+				return nil, nil
+
+			case "fault":
+				return s.readFault()
+
+			default:
+				if err := s.skipElement(); err != nil {
+					return nil, err
+				}
+			}
+
+		case xml.EndElement:
+			// Response without params
+			s.done = true
+			return nil, nil
+		}
+	}
+}
+
+func (s *responseStream) readFault() (*Fault, error) {
+	fault := &ResponseFault{}
+	for {
+		token, err := s.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "value" {
+				if err := s.skipElement(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			value, err := s.readValue()
+			if err != nil {
+				return nil, err
+			}
+			fault.Value = *value
+
+		case xml.EndElement:
+			return s.decoder.decodeFault(fault), nil
+		}
+	}
+}
+
+// nextParam advances to the <value> of the next param, returning false when there are no more params.
+func (s *responseStream) nextParam() (bool, error) {
+	if s.done {
+		return false, nil
+	}
+
+	if s.inParam {
+		// Rest of the previous param, after its value
+		s.inParam = false
+		if err := s.skipElement(); err != nil {
+			return false, err
+		}
+	}
+
+	for {
+		token, err := s.next()
+		if err != nil {
+			return false, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "param" {
+				if err := s.skipElement(); err != nil {
+					return false, err
+				}
+				continue
+			}
+
+			if err := s.openParam(); err != nil {
+				return false, err
+			}
+			s.inParam = true
+			return true, nil
+
+		case xml.EndElement:
+			s.done = true
+			return false, nil
+		}
+	}
+}
+
+// openParam reads contents of a <param> up to start of its value.
+func (s *responseStream) openParam() error {
+	for {
+		token, err := s.next()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "value" {
+				return nil
+			}
+			if err := s.skipElement(); err != nil {
+				return err
+			}
+
+		case xml.EndElement:
+			return errors.New("param without value")
+		}
+	}
+}
+
+// skipParams skips values of remaining params, returning their number.
+func (s *responseStream) skipParams() (int, error) {
+	n := 0
+	for {
+		ok, err := s.nextParam()
+		if err != nil || !ok {
+			return n, err
+		}
+		if err := s.skipElement(); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+// decodeParams decodes params of the response into v, following the rules of Decode, and reads the rest of the response.
+func (s *responseStream) decodeParams(v interface{}) error {
+	if err := s.decodeParamValues(v); err != nil {
+		return err
+	}
+
+	return s.finish()
+}
+
+// finish reads the rest of the response after its params, so that it is checked for syntax and limits,
+// and a connection the response is read from can be reused.
+func (s *responseStream) finish() error {
+	if _, err := s.skipParams(); err != nil {
+		return err
+	}
+
+	for {
+		token, err := s.dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if s.scanner != nil {
+			if err := s.scanner.token(token); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *responseStream) decodeParamValues(v interface{}) error {
+	if rv := reflect.ValueOf(v); rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer, got %T", v)
+	}
+	vElem := reflect.Indirect(reflect.ValueOf(v))
+
+	switch {
+	case isParamsStruct(vElem.Type()):
+		return s.decodeParamsStruct(v, vElem)
+
+	case vElem.Type() == paramListAnyType || vElem.Type() == paramListValueType:
+		slice := reflect.MakeSlice(vElem.Type(), 0, 0)
+		for i := 0; ; i++ {
+			ok, err := s.nextParam()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+
+			slice = reflect.Append(slice, reflect.Zero(vElem.Type().Elem()))
+			if err := s.decodeValue(slice.Index(i)); err != nil {
+				return fmt.Errorf("failed decoding param at index %d: %w", i, err)
+			}
+		}
+		vElem.Set(slice)
+
+		return nil
+
+	default:
+		ok, err := s.nextParam()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if !s.decoder.lenientParams {
+				return fmt.Errorf("number of params (%d) doesnt match expectation (1) of response type %T", 0, v)
+			}
+			return nil
+		}
+
+		if err := s.decodeValue(vElem); err != nil {
+			return err
+		}
+
+		extra, err := s.skipParams()
+		if err != nil {
+			return err
+		}
+		if extra != 0 && !s.decoder.lenientParams {
+			return fmt.Errorf("number of params (%d) doesnt match expectation (1) of response type %T", 1+extra, v)
+		}
+
+		return nil
+	}
+}
+
+// decodeParamsStruct decodes params into a struct by field position, or a single <struct> or <array> param into
+// the struct itself, as StdDecoder.decodeParamsStruct does.
+//
+// As the number of params is not known up front, a first <struct> or <array> param is decoded directly into the struct,
+// unless its first field could receive it as well. Only then the param is read into a ResponseValue, until it is known
+// whether other params follow.
+func (s *responseStream) decodeParamsStruct(v interface{}, vElem reflect.Value) error {
+	fields := exportedFields(vElem)
+
+	ok, err := s.nextParam()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if s.decoder.lenientParams {
+			return nil
+		}
+		return fieldsMustEqual(v, 0)
+	}
+
+	start := s.dec.InputOffset()
+	typ, raw, err := s.openValue()
+	if err != nil {
+		return err
+	}
+
+	container := typ != nil && (typ.Name.Local == "struct" || typ.Name.Local == "array")
+	switch {
+	case !container || len(fields) == 1:
+		if len(fields) == 0 {
+			if err := s.skipOpenedValue(typ); err != nil {
+				return err
+			}
+		} else if err := s.decodeOpenedValue(fields[0], start, typ, raw); err != nil {
+			return err
+		}
+
+		return s.decodeParamFields(v, fields, 1)
+
+	case len(fields) == 0 || !acceptsContainer(fields[0].Type(), typ.Name.Local):
+		if err := s.decodeOpenedValue(vElem, start, typ, raw); err != nil {
+			return err
+		}
+
+		extra, err := s.skipParams()
+		if err != nil {
+			return err
+		}
+		if extra != 0 {
+			if err := fieldsMustEqual(v, 1+extra); err != nil && !s.decoder.lenientParams {
+				return err
+			}
+			// Params are decoded by position then, and the first field cannot receive the param
+			return fmt.Errorf("cannot decode <%s> param into field of type '%s'", typ.Name.Local, fields[0].Type())
+		}
+
+		return nil
+
+	default:
+		first, err := s.readOpenedValue(start, typ)
+		if err != nil {
+			return err
+		}
+
+		if ok, err = s.nextParam(); err != nil {
+			return err
+		}
+		if !ok {
+			// Lenient params are decoded by position, as the first field can receive the param
+			if s.decoder.lenientParams {
+				return s.decoder.decodeValue(first, fields[0])
+			}
+			return s.decoder.decodeValue(first, vElem)
+		}
+
+		if err := s.decoder.decodeValue(first, fields[0]); err != nil {
+			return err
+		}
+		if err := s.decodeValue(fields[1]); err != nil {
+			return err
+		}
+
+		return s.decodeParamFields(v, fields, 2)
+	}
+}
+
+// decodeParamFields decodes params following the ones already decoded into fields by position.
+func (s *responseStream) decodeParamFields(v interface{}, fields []reflect.Value, decoded int) error {
+	for ; ; decoded++ {
+		ok, err := s.nextParam()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+
+		if decoded < len(fields) {
+			err = s.decodeValue(fields[decoded])
+		} else {
+			err = s.skipElement()
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if !s.decoder.lenientParams {
+		return fieldsMustEqual(v, decoded)
+	}
+
+	return nil
+}
+
+// acceptsContainer reports whether a field of type t can receive a value of container type typ ("struct" or "array").
+func acceptsContainer(t reflect.Type, typ string) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == valueType || t == rawValueType || t.Kind() == reflect.Interface:
+		return true
+	case typ == "struct":
+		return t == orderedStructType || t.Kind() == reflect.Map || (t.Kind() == reflect.Struct && !isTuple(t))
+	default:
+		return (t.Kind() == reflect.Slice && t != orderedStructType) || t.Kind() == reflect.Array || (t.Kind() == reflect.Struct && isTuple(t))
+	}
+}
+
+// isDynamic reports whether values decoded into type t depend on more than a single value type,
+// which is the case for dynamic targets, tuples and fixed-size arrays.
+func isDynamic(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t == valueType || t == rawValueType || t == orderedStructType ||
+		t.Kind() == reflect.Interface || t.Kind() == reflect.Array || (t.Kind() == reflect.Struct && isTuple(t))
+}
+
+// openValue reads contents of a <value>, whose start was just read, up to its type element.
+// Contents of the value are recorded, until stopped. For untyped values, the whole value is read and its contents returned.
+func (s *responseStream) openValue() (*xml.StartElement, []byte, error) {
+	s.rec.start()
+	start := s.dec.InputOffset()
+
+	for {
+		token, err := s.next()
+		if err != nil {
+			s.rec.stop()
+			return nil, nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			typ := t.Copy()
+			return &typ, nil, nil
+
+		case xml.EndElement:
+			s.rec.stop()
+			return nil, s.rec.buf[:s.offset-start], nil
+		}
+	}
+}
+
+// decodeValue decodes a <value>, whose start was just read, into the field.
+func (s *responseStream) decodeValue(field reflect.Value) error {
+	start := s.dec.InputOffset()
+
+	typ, raw, err := s.openValue()
+	if err != nil {
+		return err
+	}
+
+	return s.decodeOpenedValue(field, start, typ, raw)
+}
+
+// decodeOpenedValue decodes a <value> opened by openValue into the field.
+func (s *responseStream) decodeOpenedValue(field reflect.Value, start int64, typ *xml.StartElement, raw []byte) error {
+	if typ == nil {
+		return s.decoder.decodeValue(&ResponseValue{RawXML: string(raw)}, field)
+	}
+
+	if w, ok := s.decoder.sinkOf(field, typ.Name.Local == "base64"); ok {
+		s.rec.stop()
+		if err := s.decodeBase64To(w); err != nil {
+			return err
+		}
+		return s.closeValue()
+	}
+
+	if isDynamic(field.Type()) || !streamedTypes[typ.Name.Local] {
+		value, err := s.readOpenedValue(start, typ)
+		if err != nil {
+			return err
+		}
+
+		return s.decoder.decodeValue(value, field)
+	}
+
+	s.rec.stop()
+	if err := s.decodeTyped(field, typ); err != nil {
+		return err
+	}
+
+	return s.closeValue()
+}
+
+// readValue reads a <value>, whose start was just read, into a ResponseValue.
+func (s *responseStream) readValue() (*ResponseValue, error) {
+	start := s.dec.InputOffset()
+
+	typ, raw, err := s.openValue()
+	if err != nil {
+		return nil, err
+	}
+	if typ == nil {
+		return &ResponseValue{RawXML: string(raw)}, nil
+	}
+
+	return s.readOpenedValue(start, typ)
+}
+
+// readOpenedValue reads the rest of a <value> opened by openValue into a ResponseValue, out of its recorded contents.
+func (s *responseStream) readOpenedValue(start int64, typ *xml.StartElement) (*ResponseValue, error) {
+	defer s.rec.stop()
+
+	for depth := 1; depth >= 0; {
+		token, err := s.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	value := &ResponseValue{}
+	if err := xml.Unmarshal(RawValue(s.rec.buf[:s.offset-start]).element(), value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// skipOpenedValue skips the rest of a <value> opened by openValue.
+func (s *responseStream) skipOpenedValue(typ *xml.StartElement) error {
+	s.rec.stop()
+	if typ == nil {
+		return nil
+	}
+
+	if err := s.skipElement(); err != nil {
+		return err
+	}
+
+	return s.closeValue()
+}
+
+// closeValue reads the rest of a <value> after its type element. Any other elements are ignored, as by NewResponse.
+func (s *responseStream) closeValue() error {
+	for {
+		token, err := s.next()
+		if err != nil {
+			return err
+		}
+
+		switch token.(type) {
+		case xml.StartElement:
+			if err := s.skipElement(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// decodeTyped decodes contents of a type element, whose start was just read, into the field.
+func (s *responseStream) decodeTyped(field reflect.Value, typ *xml.StartElement) error {
+	switch typ.Name.Local {
+	case "nil":
+		if err := s.skipElement(); err != nil {
+			return err
+		}
+
+		for !field.CanSet() && field.Kind() == reflect.Ptr && !field.IsNil() {
+			field = field.Elem()
+		}
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+
+	case "array":
+		return s.decodeArray(indirect(field))
+
+	case "struct":
+		return s.decodeStruct(indirect(field))
+	}
+
+	text, err := s.readText()
+	if err != nil {
+		return err
+	}
+
+	field = indirect(field)
+
+	var val interface{}
+	switch typ.Name.Local {
+	case "i4", "int":
+		val, err = s.decoder.decodeInt(text)
+	case "double":
+		val, err = s.decoder.decodeDouble(text)
+	case "boolean":
+		val, err = s.decoder.decodeBoolean(text)
+	case "string":
+		val = text
+	case "base64":
+		val, err = s.decoder.decodeBase64(text)
+	case "dateTime.iso8601":
+		val, err = s.decoder.decodeDateTime(text)
+	}
+	if err != nil {
+		return err
+	}
+
+	if field.Kind() == reflect.Bool {
+		val = s.decoder.lenientBoolean(val)
+	}
+	return assignValue(field, val)
+}
+
+// decodeArray decodes contents of an <array> into a slice. Empty arrays leave the field untouched, as in Decode.
+func (s *responseStream) decodeArray(field reflect.Value) error {
+	if field.Kind() != reflect.Slice {
+		return fmt.Errorf(errFormatInvalidFieldType, reflect.Slice.String(), field.Kind().String())
+	}
+
+	slice := reflect.MakeSlice(field.Type(), 0, 0)
+	for {
+		token, err := s.next()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "data" {
+				if err := s.skipElement(); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if slice, err = s.decodeArrayData(slice); err != nil {
+				return err
+			}
+
+		case xml.EndElement:
+			if slice.Len() != 0 {
+				field.Set(slice)
+			}
+			return nil
+		}
+	}
+}
+
+// decodeArrayData appends values of array <data> to the slice.
+func (s *responseStream) decodeArrayData(slice reflect.Value) (reflect.Value, error) {
+	for {
+		token, err := s.next()
+		if err != nil {
+			return slice, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "value" {
+				if err := s.skipElement(); err != nil {
+					return slice, err
+				}
+				continue
+			}
+
+			i := slice.Len()
+			slice = reflect.Append(slice, reflect.Zero(slice.Type().Elem()))
+			if err := s.decodeValue(slice.Index(i)); err != nil {
+				return slice, fmt.Errorf("failed decoding array item at index %d: %w", i, err)
+			}
+
+		case xml.EndElement:
+			return slice, nil
+		}
+	}
+}
+
+// decodeStruct decodes members of a <struct> into a struct or a map with string keys.
+func (s *responseStream) decodeStruct(field reflect.Value) error {
+	kind := field.Kind()
+	if kind != reflect.Struct && kind != reflect.Map {
+		return fmt.Errorf(errFormatInvalidFieldTypeOrType, reflect.Struct.String(), reflect.Map.String(), kind.String())
+	}
+
+	if kind == reflect.Map {
+		if kt := field.Type().Key().Kind(); kt != reflect.String {
+			return fmt.Errorf(errFormatInvalidMapKeyTypeForStruct, kt.String())
+		}
+
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(field.Type()))
+		}
+	}
+
+	for {
+		token, err := s.next()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "member" {
+				err = s.skipElement()
+			} else {
+				err = s.decodeMember(field)
+			}
+			if err != nil {
+				return err
+			}
+
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// decodeMember decodes a struct <member>. A value preceding the member name is read into a ResponseValue,
+// to be decoded once the name is known.
+func (s *responseStream) decodeMember(field reflect.Value) error {
+	var name string
+	var named bool
+	var value *ResponseValue
+
+	for {
+		token, err := s.next()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Local == "name":
+				name, err = s.readText()
+				named = true
+			case t.Name.Local == "value" && named:
+				err = s.decodeMemberValue(field, name, nil)
+			case t.Name.Local == "value":
+				value, err = s.readValue()
+			default:
+				err = s.skipElement()
+			}
+			if err != nil {
+				return err
+			}
+
+		case xml.EndElement:
+			if value != nil {
+				return s.decodeMemberValue(field, name, value)
+			}
+			return nil
+		}
+	}
+}
+
+// decodeMemberValue decodes value of a struct member into the struct field or map entry. Unless value is already read,
+// it is decoded from the <value>, whose start was just read.
+func (s *responseStream) decodeMemberValue(field reflect.Value, name string, value *ResponseValue) error {
+	decode := func(target reflect.Value) error {
+		if value != nil {
+			return s.decoder.decodeValue(value, target)
+		}
+		return s.decodeValue(target)
+	}
+
+	if field.Kind() == reflect.Map {
+		f := reflect.New(field.Type().Elem()).Elem()
+		if err := decode(f); err != nil {
+			return fmt.Errorf("failed decoding struct member '%s': %w", name, err)
+		}
+		field.SetMapIndex(reflect.ValueOf(name), f)
+
+		return nil
+	}
+
+	f := planOf(field.Type()).field(field, name)
+	if !f.IsValid() {
+		if !s.decoder.skipUnknownFields {
+			return fmt.Errorf("cannot find field '%s' on struct", structMemberToFieldName(name))
+		}
+		if value == nil {
+			return s.skipElement()
+		}
+		return nil
+	}
+
+	if err := decode(f); err != nil {
+		return fmt.Errorf("failed decoding struct member '%s': %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,400 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestStdDecoder_DecodeStream_SameAsDecodeRaw decodes every response of testdata into a range of targets,
+// expecting the same outcome from DecodeStream as from DecodeRaw.
+func TestStdDecoder_DecodeStream_SameAsDecodeRaw(t *testing.T) {
+	type Bug struct {
+		Id             int
+		Summary        string
+		IsOpen         bool
+		Score          float64
+		LastChangeTime time.Time
+		Status         string
+	}
+
+	targets := map[string]func() any{
+		"any":              func() any { return new(any) },
+		"[]any":            func() any { return &[]any{} },
+		"[]Value":          func() any { return &[]Value{} },
+		"Value":            func() any { return &Value{} },
+		"RawValue":         func() any { return &RawValue{} },
+		"OrderedStruct":    func() any { return &OrderedStruct{} },
+		"string":           func() any { return new(string) },
+		"*string":          func() any { return new(*string) },
+		"int":              func() any { return new(int) },
+		"[]int":            func() any { return &[]int{} },
+		"map[string]any":   func() any { return &map[string]any{} },
+		"map[string]Value": func() any { return &map[string]Value{} },
+		"[2]any":           func() any { return &[2]any{} },
+		"struct of 1":      func() any { return &struct{ A any }{} },
+		"struct of 2":      func() any { return &struct{ A, B any }{} },
+		"struct of scalars": func() any {
+			return &struct {
+				A string
+				B int
+			}{}
+		},
+		"struct of slices": func() any {
+			return &struct {
+				A []any
+				B []string
+			}{}
+		},
+		"struct of members": func() any {
+			return &struct {
+				Foo         string
+				Baz         int
+				WoBleBobble bool
+				Array       []any
+				Bugs        []Bug
+			}{}
+		},
+		"bugs": func() any {
+			return &struct {
+				Bugs   []Bug
+				Faults []any
+			}{}
+		},
+	}
+
+	files, err := filepath.Glob(filepath.Join("testdata", "response_*.xml"))
+	require.NoError(t, err)
+
+	for _, file := range files {
+		for name, target := range targets {
+			for _, mode := range []StdDecoder{{}, {skipUnknownFields: true}, {lenientParams: true}} {
+				dec := mode
+				t.Run(fmt.Sprintf("%s into %s %+v", filepath.Base(file), name, dec), func(t *testing.T) {
+					body := loadTestFile(t, filepath.Base(file))
+
+					expected := target()
+					expectedErr := dec.DecodeRaw(body, expected)
+
+					actual := target()
+					actualErr := dec.DecodeStream(bytes.NewReader(body), actual)
+
+					if expectedErr != nil {
+						require.Error(t, actualErr)
+						return
+					}
+					require.NoError(t, actualErr)
+					require.Equal(t, expected, actual)
+				})
+			}
+		}
+	}
+}
+
+func TestStdDecoder_DecodeStream(t *testing.T) {
+	tests := map[string]struct {
+		body   string
+		dec    StdDecoder
+		v      any
+		expect any
+		err    string
+	}{
+		"member value before its name": {
+			body:   `<methodResponse><params><param><value><struct><member><value><int>1</int></value><name>a</name></member></struct></value></param></params></methodResponse>`,
+			v:      &map[string]int{},
+			expect: &map[string]int{"a": 1},
+		},
+		"escaped text": {
+			body:   `<methodResponse><params><param><value><string>a &amp; &lt;b&gt;</string></value></param></params></methodResponse>`,
+			v:      new(string),
+			expect: ptr("a & <b>"),
+		},
+		"untyped value is raw": {
+			body:   `<methodResponse><params><param><value>a &amp; b</value></param></params></methodResponse>`,
+			v:      new(string),
+			expect: ptr("a &amp; b"),
+		},
+		"empty struct": {
+			body:   `<methodResponse><params><param><value><struct></struct></value></param></params></methodResponse>`,
+			v:      new(map[string]any),
+			expect: &map[string]any{},
+		},
+		"response without params": {
+			body:   `<methodResponse></methodResponse>`,
+			dec:    StdDecoder{lenientParams: true},
+			v:      new(int),
+			expect: new(int),
+		},
+		"single struct param into struct - lenient": {
+			body:   `<methodResponse><params><param><value><struct><member><name>a</name><value><int>1</int></value></member></struct></value></param></params></methodResponse>`,
+			dec:    StdDecoder{lenientParams: true},
+			v:      &struct{ A, B int }{},
+			expect: &struct{ A, B int }{A: 1},
+		},
+		"unknown member": {
+			body: `<methodResponse><params><param><value><struct><member><name>a</name><value><int>1</int></value></member></struct></value></param></params></methodResponse>`,
+			v:    &struct{ B, C int }{},
+			err:  "cannot find field 'A' on struct",
+		},
+		"nil target": {
+			body: `<methodResponse><params><param><value><int>1</int></value></param></params></methodResponse>`,
+			v:    nil,
+			err:  "decode target must be a non-nil pointer, got <nil>",
+		},
+		"typed nil pointer target": {
+			body: `<methodResponse><params><param><value><int>1</int></value></param></params></methodResponse>`,
+			v:    (*int)(nil),
+			err:  "decode target must be a non-nil pointer, got *int",
+		},
+		"too many params": {
+			body: `<methodResponse><params><param><value><int>1</int></value></param><param><value><int>2</int></value></param></params></methodResponse>`,
+			v:    new(int),
+			err:  "number of params (2) doesnt match expectation (1) of response type *int",
+		},
+		"invalid array item": {
+			body: `<methodResponse><params><param><value><array><data><value><int>1</int></value><value><int>x</int></value></data></array></value></param></params></methodResponse>`,
+			v:    &[]int{},
+			err:  "failed decoding array item at index 1",
+		},
+		"truncated": {
+			body: `<methodResponse><params><param><value><array><data><value><int>1</int></value>`,
+			v:    &[]int{},
+			err:  "unexpected EOF",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.dec.DecodeStream(strings.NewReader(tt.body), tt.v)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, tt.v)
+		})
+	}
+}
+
+func TestStdDecoder_EmptyStruct(t *testing.T) {
+	tests := map[string]struct {
+		value  string
+		v      func() any
+		expect any
+	}{
+		"map of ints": {
+			value:  `<struct></struct>`,
+			v:      func() any { return new(map[string]int) },
+			expect: &map[string]int{},
+		},
+		"map of any": {
+			value:  `<struct/>`,
+			v:      func() any { return new(map[string]any) },
+			expect: &map[string]any{},
+		},
+		"any": {
+			value:  `<struct></struct>`,
+			v:      func() any { return new(any) },
+			expect: ptr(any(map[string]any{})),
+		},
+		"member of struct": {
+			value:  `<struct><member><name>tags</name><value><struct></struct></value></member></struct>`,
+			v:      func() any { return new(map[string]map[string]string) },
+			expect: &map[string]map[string]string{"tags": {}},
+		},
+		"array item": {
+			value:  `<array><data><value><struct></struct></value></data></array>`,
+			v:      func() any { return new([]map[string]int) },
+			expect: &[]map[string]int{{}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			body := []byte(`<methodResponse><params><param><value>` + tt.value + `</value></param></params></methodResponse>`)
+
+			raw := tt.v()
+			require.NoError(t, (&StdDecoder{}).DecodeRaw(body, raw))
+			require.Equal(t, tt.expect, raw)
+
+			stream := tt.v()
+			require.NoError(t, (&StdDecoder{}).DecodeStream(bytes.NewReader(body), stream))
+			require.Equal(t, tt.expect, stream)
+		})
+	}
+}
+
+func TestStdDecoder_LenientParams_SingleContainer(t *testing.T) {
+	type Bug struct {
+		Id      int
+		Summary string
+	}
+
+	tests := map[string]struct {
+		value  string
+		v      func() any
+		expect any
+	}{
+		"first field receives the param": {
+			value: `<struct><member><name>id</name><value><int>35</int></value></member></struct>`,
+			v: func() any {
+				return &struct {
+					Bug     Bug
+					Comment *string
+				}{}
+			},
+			expect: &struct {
+				Bug     Bug
+				Comment *string
+			}{Bug: Bug{Id: 35}},
+		},
+		"struct receives the param": {
+			value:  `<struct><member><name>id</name><value><int>35</int></value></member></struct>`,
+			v:      func() any { return &Bug{} },
+			expect: &Bug{Id: 35},
+		},
+		"first field receives array param": {
+			value: `<array><data><value><int>1</int></value></data></array>`,
+			v: func() any {
+				return &struct {
+					Ids   []int
+					Limit *int
+				}{}
+			},
+			expect: &struct {
+				Ids   []int
+				Limit *int
+			}{Ids: []int{1}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			body := []byte(`<methodResponse><params><param><value>` + tt.value + `</value></param></params></methodResponse>`)
+
+			raw := tt.v()
+			require.NoError(t, (&StdDecoder{lenientParams: true}).DecodeRaw(body, raw))
+			require.Equal(t, tt.expect, raw)
+
+			stream := tt.v()
+			require.NoError(t, (&StdDecoder{lenientParams: true}).DecodeStream(bytes.NewReader(body), stream))
+			require.Equal(t, tt.expect, stream)
+		})
+	}
+}
+
+func TestStdDecoder_DecodeStream_Fault(t *testing.T) {
+	dec := &StdDecoder{}
+	err := dec.DecodeStream(bytes.NewReader(loadTestFile(t, "response_fault.xml")), new(any))
+
+	fault := &Fault{}
+	require.True(t, errors.As(err, &fault))
+	require.Equal(t, &Fault{Code: 4, String: "Too many parameters."}, fault)
+}
+
+func TestStdDecoder_DecodeStream_Limits(t *testing.T) {
+	body := `<methodResponse><params><param><value><array><data>` +
+		`<value><int>1</int></value><value><int>2</int></value><value><int>3</int></value>` +
+		`</data></array></value></param></params></methodResponse>`
+
+	tests := map[string]struct {
+		limits limits
+		err    *LimitError
+	}{
+		"array length": {
+			limits: limits{arrayLength: 2},
+			err:    &LimitError{Limit: LimitArrayLength, Max: 2},
+		},
+		"response size": {
+			limits: limits{responseSize: 50},
+			err:    &LimitError{Limit: LimitResponseSize, Max: 50},
+		},
+		"within limits": {
+			limits: limits{arrayLength: 3, responseSize: int64(len(body))},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dec := &StdDecoder{}
+			stream := dec.newResponseStream(tt.limits.reader(strings.NewReader(body)), &tt.limits)
+
+			fault, err := stream.readHeader()
+			require.NoError(t, err)
+			require.Nil(t, fault)
+
+			var v []int
+			err = stream.decodeParams(&v)
+			if tt.err != nil {
+				limitErr := &LimitError{}
+				require.True(t, errors.As(err, &limitErr), "expected *LimitError, got %v", err)
+				require.Equal(t, tt.err, limitErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, []int{1, 2, 3}, v)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func BenchmarkStdDecoder_DecodeStream(b *testing.B) {
+	body := benchmarkResponse(10000)
+	type item struct {
+		Id      int
+		Name    string
+		Price   float64
+		Enabled bool
+		Tags    []string
+	}
+
+	b.Run("DecodeRaw", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v []item
+			if err := (&StdDecoder{}).DecodeRaw(body, &v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("DecodeStream", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v []item
+			if err := (&StdDecoder{}).DecodeStream(bytes.NewReader(body), &v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+}
+
+// benchmarkResponse returns a response with an array of n structs.
+func benchmarkResponse(n int) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteString(`<?xml version="1.0"?><methodResponse><params><param><value><array><data>`)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(buf, `<value><struct>`+
+			`<member><name>id</name><value><int>%d</int></value></member>`+
+			`<member><name>name</name><value><string>Item %d</string></value></member>`+
+			`<member><name>price</name><value><double>%d.5</double></value></member>`+
+			`<member><name>enabled</name><value><boolean>1</boolean></value></member>`+
+			`<member><name>tags</name><value><array><data><value><string>a</string></value><value><string>b</string></value></data></array></value></member>`+
+			`</struct></value>`, i, i, i)
+	}
+	buf.WriteString(`</data></array></value></param></params></methodResponse>`)
+
+	return buf.Bytes()
+}
@@ -1,11 +1,13 @@
 package xmlrpc
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -444,6 +446,324 @@ func TestStdDecoder_DecodeRaw_Arrays(t *testing.T) {
 	}
 }
 
+func TestStdDecoder_DecodeRaw_Nil(t *testing.T) {
+	type User struct {
+		Login    string
+		RealName *string
+		Groups   []string
+		Age      int
+	}
+
+	name := "Previous"
+	v := &struct{ User User }{
+		User: User{RealName: &name, Groups: []string{"admin"}, Age: 42},
+	}
+
+	dec := &StdDecoder{}
+	require.NoError(t, dec.DecodeRaw(loadTestFile(t, "response_nil.xml"), v))
+	require.Equal(t, User{Login: "user@example.com"}, v.User)
+
+	// Single <nil/> param resets the target itself, which is not addressable
+	body := []byte(`<methodResponse><params><param><value><nil/></value></param></params></methodResponse>`)
+	str := "previous"
+	require.NoError(t, dec.DecodeRaw(body, &str))
+	require.Empty(t, str)
+
+	ptr := &name
+	require.NoError(t, dec.DecodeRaw(body, &ptr))
+	require.Nil(t, ptr)
+
+	var raw RawValue
+	require.NoError(t, dec.DecodeRaw(body, &raw))
+	str = "previous"
+	require.NoError(t, raw.Decode(&str))
+	require.Empty(t, str)
+}
+
+func TestStdDecoder_DecodeRaw_ReplyTargets(t *testing.T) {
+	type Bug struct {
+		Id             int
+		Summary        string
+		IsOpen         bool
+		Score          float64
+		LastChangeTime time.Time
+		Status         string
+	}
+
+	str := ""
+	strings := []string{}
+	anyParams := []any{}
+	valueParams := []Value{}
+	var anyValue any
+
+	tests := map[string]struct {
+		testFile string
+		lenient  bool
+		v        interface{}
+		expect   interface{}
+		err      string
+	}{
+		"single param into any": {
+			testFile: "response_array_mixed_missing_types.xml",
+			v:        &anyValue,
+			expect:   []any{0, "4099", "O3D217AC", "<c><b>123</b></c>"},
+		},
+		"single array param into slice": {
+			testFile: "response_array.xml",
+			v:        &[]int{},
+			expect:   &[]int{10, 11, 12},
+		},
+		"single struct param into struct": {
+			testFile: "response_bugs.xml",
+			v: &struct {
+				Bugs   []Bug
+				Faults []any
+			}{},
+			expect: &struct {
+				Bugs   []Bug
+				Faults []any
+			}{
+				Bugs: []Bug{
+					{Id: 35, Summary: "Crash on startup", IsOpen: true, Score: 4.5, LastChangeTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Status: "NEW"},
+					{Id: 36, Summary: "Typo in docs", IsOpen: false, Score: 1, LastChangeTime: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC), Status: "RESOLVED"},
+				},
+			},
+		},
+		"single struct param into struct - lenient": {
+			testFile: "response_bugs.xml",
+			lenient:  true,
+			v: &struct {
+				Bugs   []Bug
+				Faults []any
+			}{},
+			expect: &struct {
+				Bugs   []Bug
+				Faults []any
+			}{
+				Bugs: []Bug{
+					{Id: 35, Summary: "Crash on startup", IsOpen: true, Score: 4.5, LastChangeTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Status: "NEW"},
+					{Id: 36, Summary: "Typo in docs", IsOpen: false, Score: 1, LastChangeTime: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC), Status: "RESOLVED"},
+				},
+			},
+		},
+		"params into []any": {
+			testFile: "response_simple.xml",
+			v:        &anyParams,
+			expect:   &[]any{"South Dakota", 12345},
+		},
+		"params into []Value": {
+			testFile: "response_simple.xml",
+			v:        &valueParams,
+			expect: &[]Value{
+				{kind: KindString, text: "South Dakota"},
+				{kind: KindInt, text: "12345"},
+			},
+		},
+		"multiple params into string": {
+			testFile: "response_simple.xml",
+			v:        &str,
+			err:      "number of params (2) doesnt match expectation (1) of response type *string",
+		},
+		"multiple params into string - lenient": {
+			testFile: "response_simple.xml",
+			lenient:  true,
+			v:        &str,
+			expect:   "South Dakota",
+		},
+		"multiple params into slice": {
+			testFile: "response_simple.xml",
+			v:        &strings,
+			err:      "number of params (2) doesnt match expectation (1) of response type *[]string",
+		},
+		"fewer fields than params": {
+			testFile: "response_simple.xml",
+			v: &struct {
+				Area string
+			}{},
+			err: "number of exported fields (1) on response type doesnt match expectation (2)",
+		},
+		"fewer fields than params - lenient": {
+			testFile: "response_simple.xml",
+			lenient:  true,
+			v: &struct {
+				Area string
+			}{},
+			expect: &struct {
+				Area string
+			}{
+				Area: "South Dakota",
+			},
+		},
+		"more fields than params - lenient": {
+			testFile: "response_simple.xml",
+			lenient:  true,
+			v: &struct {
+				unexported int
+				Area       string
+				Index      int
+				Extra      bool
+			}{},
+			expect: &struct {
+				unexported int
+				Area       string
+				Index      int
+				Extra      bool
+			}{
+				Area:  "South Dakota",
+				Index: 12345,
+			},
+		},
+		"non-pointer target": {
+			testFile: "response_array.xml",
+			v:        []int{},
+			err:      "decode target must be a non-nil pointer, got []int",
+		},
+		"non-pointer struct target": {
+			testFile: "response_simple.xml",
+			v:        struct{ Area string }{},
+			err:      "decode target must be a non-nil pointer, got struct { Area string }",
+		},
+		"nil target": {
+			testFile: "response_array.xml",
+			v:        nil,
+			err:      "decode target must be a non-nil pointer, got <nil>",
+		},
+		"typed nil pointer target": {
+			testFile: "response_array.xml",
+			v:        (*int)(nil),
+			err:      "decode target must be a non-nil pointer, got *int",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dec := &StdDecoder{}
+			dec.lenientParams = tt.lenient
+			err := dec.DecodeRaw(loadTestFile(t, tt.testFile), tt.v)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			if reflect.TypeOf(tt.expect).Kind() == reflect.Ptr {
+				require.EqualValues(t, tt.expect, tt.v)
+			} else {
+				require.EqualValues(t, tt.expect, reflect.ValueOf(tt.v).Elem().Interface())
+			}
+		})
+	}
+}
+
+func TestStdDecoder_DecodeRaw_Tuples(t *testing.T) {
+	type Torrent struct {
+		_        struct{} `xmlrpc:",tuple"`
+		Hash     string
+		Name     string
+		Size     int
+		Complete int
+	}
+
+	type ShortTorrent struct {
+		_    struct{} `xmlrpc:",tuple"`
+		Hash string
+		Name string
+	}
+
+	type NotTuple struct {
+		Hash string
+		Name string
+	}
+
+	tests := map[string]struct {
+		skipUnknown bool
+		v           interface{}
+		expect      interface{}
+		err         error
+	}{
+		"tuple structs": {
+			v: &struct {
+				Torrents []Torrent
+			}{},
+			expect: &struct {
+				Torrents []Torrent
+			}{
+				Torrents: []Torrent{
+					{Hash: "5A8D3A43F9F6A7E1B1E2C3D4E5F60718293A4B5C", Name: "debian-12.iso", Size: 658505728, Complete: 1},
+					{Hash: "0B1C2D3E4F5A6B7C8D9E0F1A2B3C4D5E6F7A8B9C", Name: "ubuntu-24.04.iso", Size: 6114656, Complete: 0},
+				},
+			},
+		},
+		"tuple struct pointers": {
+			v: &struct {
+				Torrents []*Torrent
+			}{},
+			expect: &struct {
+				Torrents []*Torrent
+			}{
+				Torrents: []*Torrent{
+					{Hash: "5A8D3A43F9F6A7E1B1E2C3D4E5F60718293A4B5C", Name: "debian-12.iso", Size: 658505728, Complete: 1},
+					{Hash: "0B1C2D3E4F5A6B7C8D9E0F1A2B3C4D5E6F7A8B9C", Name: "ubuntu-24.04.iso", Size: 6114656, Complete: 0},
+				},
+			},
+		},
+		"fixed-size arrays": {
+			v: &struct {
+				Torrents [][4]any
+			}{},
+			expect: &struct {
+				Torrents [][4]any
+			}{
+				Torrents: [][4]any{
+					{"5A8D3A43F9F6A7E1B1E2C3D4E5F60718293A4B5C", "debian-12.iso", 658505728, 1},
+					{"0B1C2D3E4F5A6B7C8D9E0F1A2B3C4D5E6F7A8B9C", "ubuntu-24.04.iso", 6114656, 0},
+				},
+			},
+		},
+		"tuple with fewer fields than values": {
+			v: &struct {
+				Torrents []ShortTorrent
+			}{},
+			err: errors.New("failed decoding array item at index 0: cannot decode array of 4 values into 'xmlrpc.ShortTorrent' of 2 elements"),
+		},
+		"tuple with fewer fields than values - skip unknown": {
+			skipUnknown: true,
+			v: &struct {
+				Torrents []ShortTorrent
+			}{},
+			expect: &struct {
+				Torrents []ShortTorrent
+			}{
+				Torrents: []ShortTorrent{
+					{Hash: "5A8D3A43F9F6A7E1B1E2C3D4E5F60718293A4B5C", Name: "debian-12.iso"},
+					{Hash: "0B1C2D3E4F5A6B7C8D9E0F1A2B3C4D5E6F7A8B9C", Name: "ubuntu-24.04.iso"},
+				},
+			},
+		},
+		"struct without tuple marker": {
+			v: &struct {
+				Torrents []NotTuple
+			}{},
+			err: fmt.Errorf("failed decoding array item at index 0: %w", fmt.Errorf(errFormatInvalidFieldType, "slice", "struct")),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dec := &StdDecoder{}
+			dec.skipUnknownFields = tt.skipUnknown
+			err := dec.DecodeRaw(loadTestFile(t, "response_array_tuple.xml"), tt.v)
+			if tt.err == nil {
+				require.NoError(t, err)
+				require.EqualValues(t, tt.expect, tt.v)
+			} else {
+				require.Error(t, err)
+				require.EqualError(t, err, tt.err.Error())
+			}
+		})
+	}
+}
+
 func TestStdDecoder_DecodeRaw_Struct_Map(t *testing.T) {
 	type DataType struct {
 		Id      string `json:"id" xmlrpc:"id"`
@@ -577,6 +897,30 @@ func Test_fieldsMustEqual(t *testing.T) {
 	}
 }
 
+func TestStdDecoder_DecodeRaw_TaggedMembers(t *testing.T) {
+	type record struct {
+		StringValue        string
+		SecondNumericValue int    `xmlrpc:"2_numeric.Value"`
+		FooBar             int    `xmlrpc:"foo_bar"`
+		OtherFooBar        string `xmlrpc:"fooBar,omitempty"`
+	}
+
+	// Members are decoded into fields tagged with their names, also when encoded by the client
+	buf := new(bytes.Buffer)
+	require.NoError(t, (&StdEncoder{}).encodeResponse(buf, record{StringValue: "bar", SecondNumericValue: 2, FooBar: 1, OtherFooBar: "a"}, true))
+	body := buf.String()
+
+	dec := &StdDecoder{}
+	for mode, decode := range map[string]func(v any) error{
+		"DecodeRaw":    func(v any) error { return dec.DecodeRaw([]byte(body), v) },
+		"DecodeStream": func(v any) error { return dec.DecodeStream(strings.NewReader(body), v) },
+	} {
+		v := &struct{ Record record }{}
+		require.NoError(t, decode(v), mode)
+		require.Equal(t, record{StringValue: "bar", SecondNumericValue: 2, FooBar: 1, OtherFooBar: "a"}, v.Record, mode)
+	}
+}
+
 func Test_findFieldByNameOrTag(t *testing.T) {
 	v := &struct {
 		Normal       string
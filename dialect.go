@@ -0,0 +1,191 @@
+package xmlrpc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// extensionsNamespace is the namespace of Apache ws-xmlrpc vendor extensions, bound to the "ex" prefix.
+const extensionsNamespace = "http://ws.apache.org/xmlrpc/namespaces/extensions"
+
+// NilPolicy defines how nil values (nil pointers, slices, maps and interfaces) are encoded.
+type NilPolicy int
+
+const (
+	// NilElement encodes nil values as <nil/>, the extension most servers accept (e.g. Python with allow_none).
+	NilElement NilPolicy = iota
+	// NilExtension encodes nil values as <ex:nil/> of Apache ws-xmlrpc vendor extensions.
+	NilExtension
+	// NilReject fails encoding of nil values, which the XML-RPC specification does not define.
+	NilReject
+)
+
+func (p NilPolicy) String() string {
+	switch p {
+	case NilElement:
+		return "element"
+	case NilExtension:
+		return "extension"
+	case NilReject:
+		return "reject"
+	default:
+		return "policy(" + strconv.Itoa(int(p)) + ")"
+	}
+}
+
+// DialectProfile describes how a family of servers bends the XML-RPC specification, configuring StdEncoder and StdDecoder together.
+// Zero profile is the default behavior of the library (same as DialectStandard). Predefined profiles may be copied and adjusted.
+type DialectProfile struct {
+	// Name of the profile, reported in errors.
+	Name string
+	// IntTag is the element integers are encoded as, "int" (the default) or "i4".
+	IntTag string
+	// TimeLayout is the layout times are encoded as in <dateTime.iso8601>, time.RFC3339 by default.
+	// Decoding accepts it as well as time.RFC3339.
+	TimeLayout string
+	// Nil sets how nil values are encoded.
+	Nil NilPolicy
+	// EmitExtensions encodes values using Apache ws-xmlrpc vendor extensions (e.g. int64 as <ex:i8>),
+	// declaring their namespace on the root element.
+	EmitExtensions bool
+	// AcceptExtensions decodes values of vendor extension types (e.g. <i8> or <ex:i8> into integers),
+	// which are otherwise decoded as raw XML.
+	AcceptExtensions bool
+	// UntypedText decodes untyped values (<value>text</value>) as unescaped text, instead of raw XML.
+	UntypedText bool
+	// LenientBooleans decodes <int> and <string> values holding 0, 1, true, false or nothing into bool fields.
+	LenientBooleans bool
+}
+
+var (
+	// DialectStandard follows the XML-RPC specification, with <nil/> for nil values. This is the default profile.
+	DialectStandard = DialectProfile{
+		Name:       "standard",
+		IntTag:     "int",
+		TimeLayout: time.RFC3339,
+		Nil:        NilElement,
+	}
+
+	// DialectPython interoperates with xmlrpc.client and xmlrpc.server of Python, created with allow_none.
+	// Python accepts extension types, but emits none of them.
+	DialectPython = DialectProfile{
+		Name:             "python",
+		IntTag:           "int",
+		TimeLayout:       "20060102T15:04:05",
+		Nil:              NilElement,
+		AcceptExtensions: true,
+	}
+
+	// DialectApache interoperates with Apache ws-xmlrpc servers with enabledForExtensions set.
+	DialectApache = DialectProfile{
+		Name:             "apache",
+		IntTag:           "i4",
+		TimeLayout:       "20060102T15:04:05",
+		Nil:              NilExtension,
+		EmitExtensions:   true,
+		AcceptExtensions: true,
+	}
+
+	// DialectPHP interoperates with PHP servers (xmlrpc extension and phpxmlrpc), which reply with untyped strings,
+	// and booleans as integers or strings.
+	DialectPHP = DialectProfile{
+		Name:            "php",
+		IntTag:          "int",
+		TimeLayout:      "20060102T15:04:05",
+		Nil:             NilElement,
+		UntypedText:     true,
+		LenientBooleans: true,
+	}
+)
+
+// intTag returns the element integers are encoded as.
+func (p *DialectProfile) intTag() string {
+	if p.IntTag == "" {
+		return "int"
+	}
+
+	return p.IntTag
+}
+
+// timeLayout returns the layout times are encoded as.
+func (p *DialectProfile) timeLayout() string {
+	if p.TimeLayout == "" {
+		return time.RFC3339
+	}
+
+	return p.TimeLayout
+}
+
+// name returns the name of the profile for errors.
+func (p *DialectProfile) name() string {
+	if p.Name == "" {
+		return DialectStandard.Name
+	}
+
+	return p.Name
+}
+
+// encodeNil writes a nil value, as the profile allows.
+func (e *StdEncoder) encodeNil(w io.Writer) error {
+	switch e.dialect.Nil {
+	case NilExtension:
+		_, _ = io.WriteString(w, "<value><ex:nil/></value>")
+	case NilReject:
+		return fmt.Errorf("nil values are not supported by %s dialect", e.dialect.name())
+	default:
+		_, _ = io.WriteString(w, "<value><nil/></value>")
+	}
+
+	return nil
+}
+
+// rootAttributes returns attributes of the root element of documents, declaring the namespace of extensions when emitted.
+func (e *StdEncoder) rootAttributes() string {
+	if e.dialect.EmitExtensions || e.dialect.Nil == NilExtension {
+		return ` xmlns:ex="` + extensionsNamespace + `"`
+	}
+
+	return ""
+}
+
+// decodeUntyped returns the value of an untyped <value>, which is its raw XML unless the profile decodes it as text.
+func (d *StdDecoder) decodeUntyped(raw string) (string, error) {
+	if !d.dialect.UntypedText {
+		return raw, nil
+	}
+
+	var text struct {
+		Text string `xml:",chardata"`
+	}
+	if err := xml.Unmarshal([]byte("<value>"+raw+"</value>"), &text); err != nil {
+		return "", fmt.Errorf("cannot decode untyped value: %w", err)
+	}
+
+	return text.Text, nil
+}
+
+// lenientBoolean converts integers and strings decoded into a bool field into booleans, if the profile allows it.
+func (d *StdDecoder) lenientBoolean(val interface{}) interface{} {
+	if !d.dialect.LenientBooleans {
+		return val
+	}
+
+	switch v := val.(type) {
+	case int:
+		switch v {
+		case 0:
+			return false
+		case 1:
+			return true
+		}
+	case string:
+		if b, err := d.decodeBoolean(v); err == nil {
+			return b
+		}
+	}
+
+	return val
+}
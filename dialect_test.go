@@ -0,0 +1,257 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdEncoder_Encode_Dialect(t *testing.T) {
+	when := time.Date(2024, 5, 17, 9, 30, 0, 0, time.UTC)
+	args := Args{42, when, (*string)(nil)}
+
+	tests := map[string]struct {
+		dialect DialectProfile
+		args    any
+		expect  string
+		err     string
+	}{
+		"default": {
+			args:   args,
+			expect: `<methodCall><methodName>m</methodName><params><param><value><int>42</int></value></param><param><value><dateTime.iso8601>2024-05-17T09:30:00Z</dateTime.iso8601></value></param><param><value><nil/></value></param></params></methodCall>`,
+		},
+		"standard": {
+			dialect: DialectStandard,
+			args:    args,
+			expect:  `<methodCall><methodName>m</methodName><params><param><value><int>42</int></value></param><param><value><dateTime.iso8601>2024-05-17T09:30:00Z</dateTime.iso8601></value></param><param><value><nil/></value></param></params></methodCall>`,
+		},
+		"python": {
+			dialect: DialectPython,
+			args:    args,
+			expect:  `<methodCall><methodName>m</methodName><params><param><value><int>42</int></value></param><param><value><dateTime.iso8601>20240517T09:30:00</dateTime.iso8601></value></param><param><value><nil/></value></param></params></methodCall>`,
+		},
+		"apache": {
+			dialect: DialectApache,
+			args:    Args{42, when, (*string)(nil), int64(1) << 40},
+			expect: `<methodCall xmlns:ex="http://ws.apache.org/xmlrpc/namespaces/extensions"><methodName>m</methodName><params>` +
+				`<param><value><i4>42</i4></value></param><param><value><dateTime.iso8601>20240517T09:30:00</dateTime.iso8601></value></param>` +
+				`<param><value><ex:nil/></value></param><param><value><ex:i8>1099511627776</ex:i8></value></param></params></methodCall>`,
+		},
+		"php": {
+			dialect: DialectPHP,
+			args:    args,
+			expect:  `<methodCall><methodName>m</methodName><params><param><value><int>42</int></value></param><param><value><dateTime.iso8601>20240517T09:30:00</dateTime.iso8601></value></param><param><value><nil/></value></param></params></methodCall>`,
+		},
+		"nil rejected": {
+			dialect: DialectProfile{Name: "strict", Nil: NilReject},
+			args:    Args{[]any{1, nil}},
+			err:     "cannot encode array element at index 1: nil values are not supported by strict dialect",
+		},
+		"nil Value rejected": {
+			dialect: DialectProfile{Nil: NilReject},
+			args:    Args{Value{kind: KindNil}},
+			err:     "nil values are not supported by standard dialect",
+		},
+		"int64 without extensions": {
+			dialect: DialectPython,
+			args:    Args{int64(1)},
+			err:     "unsupported type int64",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := (&StdEncoder{dialect: tt.dialect}).Encode(buf, "m", tt.args)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, buf.String())
+		})
+	}
+}
+
+func TestStdDecoder_Decode_Dialect(t *testing.T) {
+	response := func(value string) string {
+		return `<methodResponse><params><param><value>` + value + `</value></param></params></methodResponse>`
+	}
+
+	tests := map[string]struct {
+		dialect DialectProfile
+		body    string
+		v       any
+		expect  any
+		err     string
+	}{
+		"time of dialect": {
+			dialect: DialectPython,
+			body:    response(`<dateTime.iso8601>20240517T09:30:00</dateTime.iso8601>`),
+			v:       new(time.Time),
+			expect:  ptr(time.Date(2024, 5, 17, 9, 30, 0, 0, time.UTC)),
+		},
+		"RFC 3339 time with dialect": {
+			dialect: DialectPython,
+			body:    response(`<dateTime.iso8601>2024-05-17T09:30:00Z</dateTime.iso8601>`),
+			v:       new(time.Time),
+			expect:  ptr(time.Date(2024, 5, 17, 9, 30, 0, 0, time.UTC)),
+		},
+		"time of dialect by default": {
+			body: response(`<dateTime.iso8601>20240517T09:30:00</dateTime.iso8601>`),
+			v:    new(time.Time),
+			err:  "cannot parse",
+		},
+		"nil extension": {
+			dialect: DialectApache,
+			body:    response(`<ex:nil/>`),
+			v:       ptr(ptr("a")),
+			expect:  ptr((*string)(nil)),
+		},
+		"i8 extension": {
+			dialect: DialectApache,
+			body:    response(`<ex:i8>1099511627776</ex:i8>`),
+			v:       new(int64),
+			expect:  ptr(int64(1) << 40),
+		},
+		"i8 into any": {
+			dialect: DialectPython,
+			body:    response(`<i8>-5</i8>`),
+			v:       new(any),
+			expect:  ptr(any(int64(-5))),
+		},
+		"i8 without extensions": {
+			body:   response(`<ex:i8>5</ex:i8>`),
+			v:      new(string),
+			expect: ptr(`<ex:i8>5</ex:i8>`),
+		},
+		"serializable extension": {
+			dialect: DialectApache,
+			body:    response(`<ex:serializable>rO0ABQ==</ex:serializable>`),
+			v:       new([]byte),
+			expect:  ptr([]byte{0xac, 0xed, 0x00, 0x05}),
+		},
+		"untyped text": {
+			dialect: DialectPHP,
+			body:    response(`Fish &amp; Chips`),
+			v:       new(string),
+			expect:  ptr("Fish & Chips"),
+		},
+		"untyped raw XML by default": {
+			body:   response(`Fish &amp; Chips`),
+			v:      new(string),
+			expect: ptr("Fish &amp; Chips"),
+		},
+		"boolean as int": {
+			dialect: DialectPHP,
+			body:    response(`<int>1</int>`),
+			v:       new(bool),
+			expect:  ptr(true),
+		},
+		"boolean as string": {
+			dialect: DialectPHP,
+			body:    response(`<string>false</string>`),
+			v:       ptr(true),
+			expect:  ptr(false),
+		},
+		"boolean as untyped": {
+			dialect: DialectPHP,
+			body:    response(`1`),
+			v:       new(bool),
+			expect:  ptr(true),
+		},
+		"boolean out of range": {
+			dialect: DialectPHP,
+			body:    response(`<int>2</int>`),
+			v:       new(bool),
+			err:     "type 'bool' cannot be assigned a value of type 'int'",
+		},
+		"boolean as int by default": {
+			body: response(`<int>1</int>`),
+			v:    new(bool),
+			err:  "type 'bool' cannot be assigned a value of type 'int'",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dec := &StdDecoder{dialect: tt.dialect}
+			for mode, decode := range map[string]func(v any) error{
+				"DecodeRaw":    func(v any) error { return dec.DecodeRaw([]byte(tt.body), v) },
+				"DecodeStream": func(v any) error { return dec.DecodeStream(strings.NewReader(tt.body), v) },
+			} {
+				v := clone(tt.v)
+				err := decode(v)
+				if tt.err != "" {
+					require.ErrorContains(t, err, tt.err, mode)
+					continue
+				}
+
+				require.NoError(t, err, mode)
+				require.Equal(t, tt.expect, v, mode)
+			}
+		})
+	}
+}
+
+// clone returns a copy of what v points to, so a target can be decoded into more than once.
+func clone(v any) any {
+	switch v := v.(type) {
+	case **string:
+		return ptr(ptr(**v))
+	case *string:
+		return ptr(*v)
+	case *bool:
+		return ptr(*v)
+	case *int64:
+		return ptr(*v)
+	case *time.Time:
+		return ptr(*v)
+	case *[]byte:
+		return ptr(*v)
+	case *any:
+		return ptr(*v)
+	default:
+		panic("cannot clone target")
+	}
+}
+
+func TestClient_Option_Dialect(t *testing.T) {
+	var request string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		request = string(body)
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><methodResponse xmlns:ex="http://ws.apache.org/xmlrpc/namespaces/extensions"><params><param><value><struct>` +
+			`<member><name>id</name><value><ex:i8>9007199254740993</ex:i8></value></member>` +
+			`<member><name>updated</name><value><dateTime.iso8601>20240517T09:30:00</dateTime.iso8601></value></member>` +
+			`<member><name>owner</name><value><ex:nil/></value></member>` +
+			`</struct></value></param></params></methodResponse>`))
+	}))
+	defer ts.Close()
+
+	type record struct {
+		Id      int64
+		Updated time.Time
+		Owner   *string
+	}
+
+	for _, stream := range []bool{false, true} {
+		c, err := NewClient(ts.URL, Dialect(DialectApache), StreamResponses(stream))
+		require.NoError(t, err)
+
+		v := &record{Owner: ptr("nobody")}
+		require.NoError(t, c.CallContext(context.Background(), "records.get", Args{7, (*string)(nil)}, v))
+		require.Equal(t, &record{Id: 9007199254740993, Updated: time.Date(2024, 5, 17, 9, 30, 0, 0, time.UTC)}, v)
+		require.Contains(t, request, `<methodCall xmlns:ex="http://ws.apache.org/xmlrpc/namespaces/extensions">`)
+		require.Contains(t, request, `<param><value><i4>7</i4></value></param><param><value><ex:nil/></value></param>`)
+		require.NoError(t, c.Close())
+	}
+}
@@ -1,11 +1,15 @@
 package xmlrpc
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/base64"
-	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -14,24 +18,120 @@ type Encoder interface {
 	Encode(w io.Writer, methodName string, args interface{}) error
 }
 
+// Args is a list of positional method arguments, where each element is encoded as a separate param.
+// It allows passing arguments without declaring a wrapper struct.
+type Args []any
+
 // StdEncoder is the default implementation of Encoder interface.
-type StdEncoder struct{}
+type StdEncoder struct {
+	sortMapKeys  bool
+	progress     ProgressFunc
+	invalidChars InvalidCharPolicy
+	// skipMethodNameValidation allows any method name, which is then escaped
+	skipMethodNameValidation bool
+	// xmlDeclaration is written before requests, and always along with a charset
+	xmlDeclaration bool
+	charset        string
+	dialect        DialectProfile
+}
+
+// writerPool holds buffered writers of Encode, which merge the many small writes of encoding.
+var writerPool = sync.Pool{
+	New: func() any {
+		return bufio.NewWriter(nil)
+	},
+}
 
+// Encode writes a method call to w. Output is buffered and flushed once the call is encoded, failing with the first error of writing to w.
 func (e *StdEncoder) Encode(w io.Writer, methodName string, args interface{}) error {
-	_, _ = fmt.Fprintf(w, "<methodCall><methodName>%s</methodName>", methodName)
+	bw := writerPool.Get().(*bufio.Writer)
+	bw.Reset(w)
+	defer func() {
+		bw.Reset(nil)
+		writerPool.Put(bw)
+	}()
+
+	name := new(bytes.Buffer)
+	if err := e.encodeMethodName(name, methodName); err != nil {
+		return err
+	}
+
+	var out io.Writer = bw
+	var cw *charsetWriter
+	switch {
+	case e.charset != "":
+		enc, name, err := requestCharset(e.charset)
+		if err != nil {
+			return err
+		}
+		if enc != nil {
+			cw = newCharsetWriter(bw, enc, name)
+			out = cw
+		}
+		_, _ = fmt.Fprintf(out, `<?xml version="1.0" encoding="%s"?>`, name)
+	case e.xmlDeclaration:
+		_, _ = io.WriteString(out, `<?xml version="1.0"?>`)
+	}
+
+	_, _ = fmt.Fprintf(out, "<methodCall%s><methodName>%s</methodName>", e.rootAttributes(), name)
 
 	if args != nil {
-		if err := e.encodeArgs(w, args); err != nil {
+		if err := e.encodeArgs(out, args); err != nil {
 			return fmt.Errorf("cannot encoded provided method arguments: %w", err)
 		}
 	}
 
-	_, _ = fmt.Fprint(w, "</methodCall>")
+	if _, err := io.WriteString(out, "</methodCall>"); err != nil {
+		return err
+	}
+	if cw != nil {
+		if err := cw.Close(); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// encodeResponse writes a successful method response. Reply is encoded as the single param, unless it is nil.
+func (e *StdEncoder) encodeResponse(w io.Writer, reply any, hasReply bool) error {
+	_, _ = fmt.Fprintf(w, "<methodResponse%s><params>", e.rootAttributes())
+	if hasReply {
+		_, _ = io.WriteString(w, "<param>")
+		if err := e.encodeValue(w, reply); err != nil {
+			return fmt.Errorf("cannot encode reply: %w", err)
+		}
+		_, _ = io.WriteString(w, "</param>")
+	}
+	_, _ = io.WriteString(w, "</params></methodResponse>")
+
+	return nil
+}
+
+// encodeFault writes a fault method response.
+func (e *StdEncoder) encodeFault(w io.Writer, fault *Fault) error {
+	_, _ = fmt.Fprintf(w, "<methodResponse><fault><value><struct><member><name>faultCode</name><value><int>%d</int></value></member>", fault.Code)
+	_, _ = io.WriteString(w, "<member><name>faultString</name>")
+	if err := e.encodeValue(w, fault.String); err != nil {
+		return err
+	}
+	_, _ = io.WriteString(w, "</member></struct></value></fault></methodResponse>")
 
 	return nil
 }
 
 func (e *StdEncoder) encodeArgs(w io.Writer, args interface{}) error {
+	switch a := args.(type) {
+	case Args:
+		return e.encodeListArgs(w, a)
+	case *Args:
+		return e.encodeListArgs(w, *a)
+	case OrderedStruct:
+		return e.encodeListArgs(w, Args{a})
+	case *OrderedStruct:
+		return e.encodeListArgs(w, Args{*a})
+	}
+
 	// Allows reading both pointer and value-structs
 	elem := reflect.Indirect(reflect.ValueOf(args))
 
@@ -45,8 +145,18 @@ func (e *StdEncoder) encodeArgs(w io.Writer, args interface{}) error {
 	}
 }
 
+// omitEmptyTagOption is the `xmlrpc` tag option of param fields, which are not sent when they are trailing and hold a zero value,
+// allowing to leave out optional params of a method (instead of sending <nil/>).
+const omitEmptyTagOption = "omitempty"
+
 func (e *StdEncoder) encodeStructArgs(w io.Writer, elem reflect.Value) error {
 	numFields := elem.NumField()
+	for ; numFields > 0; numFields-- {
+		f := elem.Type().Field(numFields - 1)
+		if f.IsExported() && (!hasTagOption(f.Tag.Get("xmlrpc"), omitEmptyTagOption) || !elem.Field(numFields-1).IsZero()) {
+			break
+		}
+	}
 	if numFields == 0 {
 		return nil
 	}
@@ -61,33 +171,51 @@ func (e *StdEncoder) encodeStructArgs(w io.Writer, elem reflect.Value) error {
 		// If this is first exported field - print out <params> tag
 		if !hasExportedFields {
 			hasExportedFields = true
-			_, _ = fmt.Fprint(w, "<params>")
+			_, _ = io.WriteString(w, "<params>")
 		}
 
-		_, _ = fmt.Fprint(w, "<param>")
+		_, _ = io.WriteString(w, "<param>")
 		if err := e.encodeValue(w, field.Interface()); err != nil {
 			return fmt.Errorf("cannot encode argument '%s': %w", elem.Type().Field(fN).Name, err)
 		}
-		_, _ = fmt.Fprint(w, "</param>")
+		_, _ = io.WriteString(w, "</param>")
 	}
 
 	// Only write closing </params> tag if at least one exported field is found
 	if hasExportedFields {
-		_, _ = fmt.Fprint(w, "</params>")
+		_, _ = io.WriteString(w, "</params>")
 	}
 
 	return nil
 }
 
+func (e *StdEncoder) encodeListArgs(w io.Writer, args Args) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	_, _ = io.WriteString(w, "<params>")
+	for i, arg := range args {
+		_, _ = io.WriteString(w, "<param>")
+		if err := e.encodeValue(w, arg); err != nil {
+			return fmt.Errorf("cannot encode argument at index %d: %w", i, err)
+		}
+		_, _ = io.WriteString(w, "</param>")
+	}
+	_, _ = io.WriteString(w, "</params>")
+
+	return nil
+}
+
 func (e *StdEncoder) encodeBareMapArgs(w io.Writer, elem reflect.Value) error {
 	if elem.Type().Key().Kind() != reflect.String {
 		return fmt.Errorf("unsupported type %s for bare map key, only string keys are supported", elem.Type().Key().Kind().String())
 	}
-	_, _ = fmt.Fprint(w, "<params><param>")
+	_, _ = io.WriteString(w, "<params><param>")
 	if err := e.encodeValue(w, elem.Interface()); err != nil {
 		return fmt.Errorf("cannot encode bare map argument: %w", err)
 	}
-	_, _ = fmt.Fprint(w, "</param></params>")
+	_, _ = io.WriteString(w, "</param></params>")
 	return nil
 }
 
@@ -97,19 +225,54 @@ func (e *StdEncoder) encodeBareMapArgs(w io.Writer, elem reflect.Value) error {
 //
 // See more: https://en.wikipedia.org/wiki/XML-RPC#Data_types
 func (e *StdEncoder) encodeValue(w io.Writer, value interface{}) error {
+	// Raw values are written as-is
+	if raw, ok := value.(RawValue); ok {
+		_, _ = fmt.Fprintf(w, "<value>%s</value>", raw)
+		return nil
+	}
+
+	// Dynamic values are written with their original data type
+	if v, ok := value.(Value); ok {
+		return e.encodeDynamicValue(w, v)
+	}
+
+	if s, ok := value.(OrderedStruct); ok {
+		_, _ = io.WriteString(w, "<value>")
+		if err := e.encodeOrderedStruct(w, s); err != nil {
+			return fmt.Errorf("cannot encode struct value: %w", err)
+		}
+		_, _ = io.WriteString(w, "</value>")
+		return nil
+	}
+
+	// Readers are streamed as <base64> data, while nil readers (e.g. a nil *os.File) are nil values
+	if r, ok := value.(io.Reader); ok && !isNil(reflect.ValueOf(r)) {
+		_, _ = io.WriteString(w, "<value>")
+		if err := e.encodeBase64Reader(w, r); err != nil {
+			return fmt.Errorf("cannot encode base64 value of reader: %w", err)
+		}
+		_, _ = io.WriteString(w, "</value>")
+		return nil
+	}
+
+	// Big numbers are only defined by vendor extensions
+	if e.dialect.EmitExtensions && e.encodeBigNumber(w, value) {
+		return nil
+	}
+
 	valueOf := reflect.ValueOf(value)
 	kind := valueOf.Kind()
 
 	// Handling pointers by following them.
-	if kind == reflect.Ptr {
-		if valueOf.IsNil() {
-			_, _ = fmt.Fprint(w, "<value><nil/></value>")
-			return nil
+	// Untyped nil (e.g. a nil element of []any) is treated the same as a nil pointer.
+	if kind == reflect.Ptr || kind == reflect.Invalid {
+		if kind == reflect.Invalid || valueOf.IsNil() {
+			return e.encodeNil(w)
 		}
 		return e.encodeValue(w, valueOf.Elem().Interface())
 	}
 
-	_, _ = fmt.Fprint(w, "<value>")
+	_, _ = io.WriteString(w, "<value>")
 	switch kind {
 	case reflect.Bool:
 		if err := e.encodeBoolean(w, value.(bool)); err != nil {
@@ -121,6 +284,12 @@ func (e *StdEncoder) encodeValue(w io.Writer, value interface{}) error {
 			return fmt.Errorf("cannot encode integer value: %w", err)
 		}
 
+	case reflect.Int8, reflect.Int16, reflect.Int64, reflect.Float32:
+		// Numbers of these sizes are only defined by vendor extensions
+		if err := e.encodeExtensionNumber(w, valueOf); err != nil {
+			return err
+		}
+
 	case reflect.Float64:
 		if err := e.encodeDouble(w, value.(float64)); err != nil {
 			return fmt.Errorf("cannot encode double value: %w", err)
@@ -144,14 +313,19 @@ func (e *StdEncoder) encodeValue(w io.Writer, value interface{}) error {
 		}
 
 	case reflect.Struct:
-		if reflect.TypeOf(value).String() != "time.Time" {
-			if err := e.encodeStruct(w, value); err != nil {
-				return fmt.Errorf("cannot encode struct value: %w", err)
-			}
-		} else {
+		switch {
+		case valueOf.Type() == timeType:
 			if err := e.encodeTime(w, value.(time.Time)); err != nil {
 				return fmt.Errorf("cannot encode time.Time value: %w", err)
 			}
+		case isTuple(valueOf.Type()):
+			if err := e.encodeTuple(w, valueOf); err != nil {
+				return fmt.Errorf("cannot encode tuple value: %w", err)
+			}
+		default:
+			if err := e.encodeStruct(w, value); err != nil {
+				return fmt.Errorf("cannot encode struct value: %w", err)
+			}
 		}
 
 	case reflect.Map:
@@ -163,7 +337,7 @@ func (e *StdEncoder) encodeValue(w io.Writer, value interface{}) error {
 		return fmt.Errorf("unsupported type %v", kind)
 	}
 
-	_, _ = fmt.Fprint(w, "</value>")
+	_, _ = io.WriteString(w, "</value>")
 	return nil
 }
 
@@ -173,7 +347,8 @@ func (e *StdEncoder) isByteArray(val interface{}) bool {
 }
 
 func (e *StdEncoder) encodeInteger(w io.Writer, val int) error {
-	_, err := fmt.Fprintf(w, "<int>%d</int>", val)
+	tag := e.dialect.intTag()
+	_, err := fmt.Fprintf(w, "<%s>%d</%s>", tag, val, tag)
 	return err
 }
 
@@ -193,54 +368,63 @@ func (e *StdEncoder) encodeBoolean(w io.Writer, val bool) error {
 }
 
 func (e *StdEncoder) encodeString(w io.Writer, val string) error {
-	_, _ = fmt.Fprint(w, "<string>")
-	if err := xml.EscapeText(w, []byte(val)); err != nil {
+	_, _ = io.WriteString(w, "<string>")
+	if err := e.escapeText(w, val); err != nil {
 		return fmt.Errorf("failed to escape string: %w", err)
 	}
-	_, _ = fmt.Fprint(w, "</string>")
+	_, _ = io.WriteString(w, "</string>")
 
 	return nil
 }
 
 func (e *StdEncoder) encodeArray(w io.Writer, val interface{}) error {
-	_, _ = fmt.Fprint(w, "<array><data>")
-	for i := 0; i < reflect.ValueOf(val).Len(); i++ {
-		if err := e.encodeValue(w, reflect.ValueOf(val).Index(i).Interface()); err != nil {
+	_, _ = io.WriteString(w, "<array><data>")
+	v := reflect.ValueOf(val)
+	for i := 0; i < v.Len(); i++ {
+		if err := e.encodeValue(w, v.Index(i).Interface()); err != nil {
 			return fmt.Errorf("cannot encode array element at index %d: %w", i, err)
 		}
 	}
 
-	_, _ = fmt.Fprint(w, "</data></array>")
+	_, _ = io.WriteString(w, "</data></array>")
+
+	return nil
+}
+
+// encodeTuple writes exported fields of a tuple struct as <array> elements, in the order they are defined on the type.
+func (e *StdEncoder) encodeTuple(w io.Writer, val reflect.Value) error {
+	_, _ = io.WriteString(w, "<array><data>")
+	for i, field := range exportedFields(val) {
+		if err := e.encodeValue(w, field.Interface()); err != nil {
+			return fmt.Errorf("cannot encode tuple element at index %d: %w", i, err)
+		}
+	}
+	_, _ = io.WriteString(w, "</data></array>")
 
 	return nil
 }
 
 func (e *StdEncoder) encodeStruct(w io.Writer, val interface{}) error {
-	_, _ = fmt.Fprint(w, "<struct>")
+	v := reflect.ValueOf(val)
+	plan := planOf(v.Type())
 
-	for i := 0; i < reflect.TypeOf(val).NumField(); i++ {
-		field := reflect.ValueOf(val).Field(i)
-		// Skip over unexported fields
-		if !field.CanInterface() {
+	_, _ = io.WriteString(w, "<struct>")
+	for i, index := range plan.exported {
+		fieldName := plan.names[i]
+		if fieldName == "" {
 			continue
 		}
-
-		fieldType := reflect.TypeOf(val).Field(i)
-		fieldName := fieldType.Name
-		tag := fieldType.Tag
-		if tag.Get("xml") != "" {
-			fieldName = tag.Get("xml")
-		} else if tag.Get("xmlrpc") != "" {
-			fieldName = tag.Get("xmlrpc")
+		_, _ = io.WriteString(w, "<member>")
+		if err := e.encodeMemberName(w, fieldName); err != nil {
+			return fmt.Errorf("cannot encode struct field '%s': %w", fieldName, err)
 		}
-		_, _ = fmt.Fprintf(w, "<member><name>%s</name>", fieldName)
 
-		if err := e.encodeValue(w, field.Interface()); err != nil {
+		if err := e.encodeValue(w, v.Field(index).Interface()); err != nil {
 			return fmt.Errorf("cannot encode value of struct field '%s': %w", fieldName, err)
 		}
-		_, _ = fmt.Fprint(w, "</member>")
+		_, _ = io.WriteString(w, "</member>")
 	}
-	_, _ = fmt.Fprint(w, "</struct>")
+	_, _ = io.WriteString(w, "</struct>")
 
 	return nil
 }
@@ -251,32 +435,93 @@ func (e *StdEncoder) encodeBase64(w io.Writer, val []byte) error {
 }
 
 func (e *StdEncoder) encodeTime(w io.Writer, val time.Time) error {
-	_, err := fmt.Fprintf(w, "<dateTime.iso8601>%s</dateTime.iso8601>", val.Format(time.RFC3339))
+	_, err := fmt.Fprintf(w, "<dateTime.iso8601>%s</dateTime.iso8601>", val.Format(e.dialect.timeLayout()))
 	return err
 }
 
 func (e *StdEncoder) encodeMap(w io.Writer, val interface{}) error {
-	_, _ = fmt.Fprint(w, "<struct>")
+	_, _ = io.WriteString(w, "<struct>")
 
 	mapValue := reflect.ValueOf(val)
-	iter := mapValue.MapRange()
+	keys := mapValue.MapKeys()
 
-	for iter.Next() {
-		key := iter.Key()
-		value := iter.Value()
+	// Convert keys to strings
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = fmt.Sprintf("%v", key.Interface())
+	}
 
-		// Convert key to string
-		keyStr := fmt.Sprintf("%v", key.Interface())
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	if e.sortMapKeys {
+		sort.Slice(order, func(i, j int) bool {
+			return names[order[i]] < names[order[j]]
+		})
+	}
 
-		_, _ = fmt.Fprintf(w, "<member><name>%s</name>", keyStr)
+	for _, i := range order {
+		keyStr := names[i]
+		_, _ = io.WriteString(w, "<member>")
+		if err := e.encodeMemberName(w, keyStr); err != nil {
+			return fmt.Errorf("cannot encode map key '%s': %w", keyStr, err)
+		}
 
-		if err := e.encodeValue(w, value.Interface()); err != nil {
+		if err := e.encodeValue(w, mapValue.MapIndex(keys[i]).Interface()); err != nil {
 			return fmt.Errorf("cannot encode map value for key '%s': %w", keyStr, err)
 		}
 
-		_, _ = fmt.Fprint(w, "</member>")
+		_, _ = io.WriteString(w, "</member>")
+	}
+
+	_, _ = io.WriteString(w, "</struct>")
+	return nil
+}
+
+// encodeDynamicValue writes a Value with its original data type, so decoded values can be sent back as they are.
+func (e *StdEncoder) encodeDynamicValue(w io.Writer, v Value) error {
+	switch v.kind {
+	case KindInvalid:
+		return errors.New("cannot encode invalid value")
+
+	case KindNil:
+		return e.encodeNil(w)
+
+	case KindUntyped:
+		// untyped values hold the raw inner XML of the value, which is written back unchanged
+		_, _ = fmt.Fprintf(w, "<value>%s</value>", v.text)
+
+	case KindArray:
+		_, _ = io.WriteString(w, "<value><array><data>")
+		for i, item := range v.items {
+			if err := e.encodeDynamicValue(w, item); err != nil {
+				return fmt.Errorf("cannot encode array element at index %d: %w", i, err)
+			}
+		}
+		_, _ = io.WriteString(w, "</data></array></value>")
+
+	case KindStruct:
+		_, _ = io.WriteString(w, "<value><struct>")
+		for _, m := range v.members {
+			_, _ = io.WriteString(w, "<member>")
+			if err := e.encodeMemberName(w, m.Name); err != nil {
+				return fmt.Errorf("cannot encode struct member '%s': %w", m.Name, err)
+			}
+			if err := e.encodeDynamicValue(w, m.Value); err != nil {
+				return fmt.Errorf("cannot encode value of struct member '%s': %w", m.Name, err)
+			}
+			_, _ = io.WriteString(w, "</member>")
+		}
+		_, _ = io.WriteString(w, "</struct></value>")
+
+	default:
+		_, _ = fmt.Fprintf(w, "<value><%s>", v.kind)
+		if err := e.escapeText(w, v.text); err != nil {
+			return fmt.Errorf("failed to escape value: %w", err)
+		}
+		_, _ = fmt.Fprintf(w, "</%s></value>", v.kind)
 	}
 
-	_, _ = fmt.Fprint(w, "</struct>")
 	return nil
 }
@@ -1,7 +1,9 @@
 package xmlrpc
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 	"time"
@@ -122,6 +124,26 @@ func TestStdEncoder_Encode(t *testing.T) {
 			},
 			paramValidator: noParamsValidator,
 		},
+		{
+			name: "Args with trailing omitempty fields",
+			args: struct {
+				Id      int
+				Zero    *string  `xmlrpc:",omitempty"`
+				Comment *string  `xmlrpc:",omitempty"`
+				Tags    []string `xmlrpc:",omitempty"`
+			}{
+				Id:      35,
+				Comment: ptr("text"),
+			},
+			paramValidator: exactParamsValidator(`<param><value><int>35</int></value></param><param><value><nil/></value></param><param><value><string>text</string></value></param>`),
+		},
+		{
+			name: "Args with all fields omitted",
+			args: struct {
+				Comment *string `xmlrpc:",omitempty"`
+			}{},
+			paramValidator: noParamsValidator,
+		},
 		{
 			name: "Boolean args",
 			args: &struct {
@@ -197,6 +219,35 @@ func TestStdEncoder_Encode(t *testing.T) {
 				`<member><name>2-.Arg</name><value><string>foo</string></value></member>`,
 			}),
 		},
+		{
+			name: "Tuple struct args - encoded as array",
+			args: &struct {
+				Torrent struct {
+					_    struct{} `xmlrpc:",tuple"`
+					Hash string
+					Size int
+				}
+			}{
+				Torrent: struct {
+					_    struct{} `xmlrpc:",tuple"`
+					Hash string
+					Size int
+				}{
+					Hash: "5A8D3A43",
+					Size: 1024,
+				},
+			},
+			paramValidator: exactParamsValidator(`<param><value><array><data><value><string>5A8D3A43</string></value><value><int>1024</int></value></data></array></value></param>`),
+		},
+		{
+			name: "Fixed-size array args",
+			args: &struct {
+				Pair [2]string
+			}{
+				Pair: [2]string{"d.name=", "d.hash="},
+			},
+			paramValidator: exactParamsValidator(`<param><value><array><data><value><string>d.name=</string></value><value><string>d.hash=</string></value></data></array></value></param>`),
+		},
 		{
 			name: "Map-based argument of a struct",
 			args: &struct {
@@ -231,6 +282,35 @@ func TestStdEncoder_Encode(t *testing.T) {
 			},
 			err: "unsupported type int for bare map key, only string keys are supported",
 		},
+		{
+			name:           "Positional args - empty",
+			args:           Args{},
+			paramValidator: noParamsValidator,
+		},
+		{
+			name: "Positional args - mixed scalar and struct",
+			args: Args{
+				"d.name",
+				12,
+				nil,
+				struct {
+					Foo string
+				}{
+					Foo: "bar",
+				},
+			},
+			paramValidator: exactParamsValidator(`<param><value><string>d.name</string></value></param><param><value><int>12</int></value></param><param><value><nil/></value></param><param><value><struct><member><name>Foo</name><value><string>bar</string></value></member></struct></value></param>`),
+		},
+		{
+			name:           "Positional args as pointer",
+			args:           &Args{true},
+			paramValidator: exactParamsValidator(`<param><value><boolean>1</boolean></value></param>`),
+		},
+		{
+			name: "Positional args - unsupported element",
+			args: Args{"ok", make(chan int)},
+			err:  "cannot encode argument at index 1: unsupported type chan",
+		},
 		{
 			name: "Unsupported argument type",
 			args: 123,
@@ -265,6 +345,42 @@ func TestStdEncoder_Encode(t *testing.T) {
 	}
 }
 
+func TestStdEncoder_Encode_WriteError(t *testing.T) {
+	w := &failingWriter{err: errors.New("connection reset")}
+	err := (&StdEncoder{}).Encode(w, "test.Method", Args{strings.Repeat("a", 10000)})
+	require.ErrorIs(t, err, w.err)
+}
+
+// failingWriter fails every write with err.
+type failingWriter struct {
+	err error
+}
+
+func (w *failingWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+func BenchmarkStdEncoder_Encode(b *testing.B) {
+	type item struct {
+		Id      int
+		Name    string
+		Price   float64
+		Enabled bool
+		Tags    []string
+	}
+	items := make([]item, 1000)
+	for i := range items {
+		items[i] = item{Id: i, Name: fmt.Sprintf("Item %d", i), Price: 1.5, Enabled: true, Tags: []string{"a", "b"}}
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := (&StdEncoder{}).Encode(io.Discard, "inventory.put", Args{items}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func TestStdEncoder_isByteArray(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -439,6 +555,21 @@ func Test_encodeStruct(t *testing.T) {
 			expect: "<struct><member><name>Name</name><value><nil/></value></member></struct>",
 			err:    nil,
 		},
+		{
+			name: "tagged fields",
+			input: struct {
+				Comment string `xmlrpc:"comment,omitempty"`
+				Id      int    `xmlrpc:",omitempty"`
+				Ignored string `xmlrpc:"-"`
+			}{
+				Comment: "c",
+				Id:      1,
+				Ignored: "i",
+			},
+			expect: "<struct><member><name>comment</name><value><string>c</string></value></member>" +
+				"<member><name>Id</name><value><int>1</int></value></member></struct>",
+			err: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -503,6 +634,21 @@ func Test_encodeTime(t *testing.T) {
 	}
 }
 
+func Test_encodeMap_Sorted(t *testing.T) {
+	enc := &StdEncoder{sortMapKeys: true}
+
+	for i := 0; i < 5; i++ {
+		buf := new(strings.Builder)
+		require.NoError(t, enc.encodeMap(buf, map[int]string{10: "ten", 2: "two", 1: "one"}))
+		// Keys are sorted as strings
+		require.Equal(t, "<struct>"+
+			"<member><name>1</name><value><string>one</string></value></member>"+
+			"<member><name>10</name><value><string>ten</string></value></member>"+
+			"<member><name>2</name><value><string>two</string></value></member>"+
+			"</struct>", buf.String())
+	}
+}
+
 func Test_encodeMap(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -0,0 +1,119 @@
+package xmlrpc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// InvalidCharPolicy defines how the encoder handles characters that XML 1.0 cannot represent (e.g. most control characters,
+// or invalid UTF-8) in strings and member names.
+type InvalidCharPolicy int
+
+const (
+	// InvalidCharReplace replaces invalid characters with U+FFFD, which is the default.
+	InvalidCharReplace InvalidCharPolicy = iota
+	// InvalidCharStrip removes invalid characters.
+	InvalidCharStrip
+	// InvalidCharReject fails encoding with an error.
+	InvalidCharReject
+)
+
+var invalidCharPolicyNames = map[InvalidCharPolicy]string{
+	InvalidCharReplace: "replace",
+	InvalidCharStrip:   "strip",
+	InvalidCharReject:  "reject",
+}
+
+func (p InvalidCharPolicy) String() string {
+	if name, ok := invalidCharPolicyNames[p]; ok {
+		return name
+	}
+
+	return "policy(" + strconv.Itoa(int(p)) + ")"
+}
+
+// methodNamePattern contains characters allowed in method names by the specification.
+var methodNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.:/]+$`)
+
+// isXMLChar reports whether r is a character allowed by XML 1.0.
+func isXMLChar(r rune) bool {
+	return r == 0x09 || r == 0x0A || r == 0x0D ||
+		(r >= 0x20 && r <= 0xD7FF) ||
+		(r >= 0xE000 && r <= 0xFFFD) ||
+		(r >= 0x10000 && r <= 0x10FFFF)
+}
+
+// escapeText writes s escaped as XML text, handling invalid characters according to the policy.
+func (e *StdEncoder) escapeText(w io.Writer, s string) error {
+	if e.invalidChars != InvalidCharReplace {
+		var err error
+		if s, err = e.invalidChars.sanitize(s); err != nil {
+			return err
+		}
+	}
+
+	// Remaining invalid characters are replaced with U+FFFD
+	return xml.EscapeText(w, []byte(s))
+}
+
+// encodeMethodName writes the method name of a call. Unless validation is skipped, names with characters not allowed
+// by the specification are rejected, otherwise the name is escaped as any other text.
+func (e *StdEncoder) encodeMethodName(w io.Writer, name string) error {
+	if e.skipMethodNameValidation {
+		return e.escapeText(w, name)
+	}
+
+	if !methodNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid method name '%s', only letters, digits and _ . : / are allowed", name)
+	}
+	_, err := io.WriteString(w, name)
+
+	return err
+}
+
+// sanitize strips invalid characters of s, or fails on the first one, depending on the policy.
+func (p InvalidCharPolicy) sanitize(s string) (string, error) {
+	var b *strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		valid := isXMLChar(r) && !(r == utf8.RuneError && size == 1)
+
+		switch {
+		case valid && b != nil:
+			b.WriteString(s[i : i+size])
+		case valid:
+		case p == InvalidCharReject:
+			if r == utf8.RuneError {
+				return "", fmt.Errorf("invalid UTF-8 at offset %d", i)
+			}
+			return "", fmt.Errorf("character %U at offset %d cannot be represented in XML", r, i)
+		case b == nil:
+			b = new(strings.Builder)
+			b.WriteString(s[:i])
+		}
+
+		i += size
+	}
+
+	if b == nil {
+		return s, nil
+	}
+
+	return b.String(), nil
+}
+
+// encodeMemberName writes an escaped <name> of a struct member.
+func (e *StdEncoder) encodeMemberName(w io.Writer, name string) error {
+	_, _ = io.WriteString(w, "<name>")
+	if err := e.escapeText(w, name); err != nil {
+		return fmt.Errorf("invalid member name: %w", err)
+	}
+	_, _ = io.WriteString(w, "</name>")
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdEncoder_Encode_MethodName(t *testing.T) {
+	tests := map[string]string{
+		"valid":             "system.listMethods",
+		"all allowed chars": "a_Z.0:9/x",
+		"empty":             "",
+		"injection":         "x</methodName><params>",
+		"space":             "system listMethods",
+		"hyphen":            "d-name",
+	}
+
+	for name, methodName := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := (&StdEncoder{}).Encode(buf, methodName, nil)
+			if methodNamePattern.MatchString(methodName) && methodName != "" {
+				require.NoError(t, err)
+				require.Equal(t, "<methodCall><methodName>"+methodName+"</methodName></methodCall>", buf.String())
+				return
+			}
+
+			require.ErrorContains(t, err, "invalid method name")
+			require.Empty(t, buf.String())
+		})
+	}
+}
+
+func TestStdEncoder_Encode_MethodName_SkipValidation(t *testing.T) {
+	tests := map[string]struct {
+		methodName string
+		expect     string
+	}{
+		"valid": {
+			methodName: "system.listMethods",
+			expect:     "system.listMethods",
+		},
+		"hyphen": {
+			methodName: "d-name",
+			expect:     "d-name",
+		},
+		"injection is escaped": {
+			methodName: "x</methodName><params>",
+			expect:     "x&lt;/methodName&gt;&lt;params&gt;",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			require.NoError(t, (&StdEncoder{skipMethodNameValidation: true}).Encode(buf, tt.methodName, nil))
+			require.Equal(t, "<methodCall><methodName>"+tt.expect+"</methodName></methodCall>", buf.String())
+		})
+	}
+}
+
+func TestStdEncoder_Encode_MemberNames(t *testing.T) {
+	type tagged struct {
+		Field int `xmlrpc:"a<b"`
+	}
+
+	tests := map[string]struct {
+		args   any
+		expect string
+	}{
+		"map key": {
+			args:   map[string]int{"a<b&c": 1},
+			expect: "<member><name>a&lt;b&amp;c</name><value><int>1</int></value></member>",
+		},
+		"struct tag": {
+			args:   Args{tagged{Field: 1}},
+			expect: "<member><name>a&lt;b</name><value><int>1</int></value></member>",
+		},
+		"ordered struct": {
+			args:   OrderedStruct{{Name: `"x">`, Value: 1}},
+			expect: "<member><name>&#34;x&#34;&gt;</name><value><int>1</int></value></member>",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			require.NoError(t, (&StdEncoder{}).Encode(buf, "test", tt.args))
+			require.Contains(t, buf.String(), tt.expect)
+
+			_, err := parseNode(buf.Bytes())
+			require.NoError(t, err, "encoded request must be well-formed")
+		})
+	}
+}
+
+func TestStdEncoder_InvalidChars(t *testing.T) {
+	args := Args{"a\x00b\x1bc\xffd", map[string]string{"k\x07": "v"}}
+
+	tests := map[InvalidCharPolicy]struct {
+		expect string
+		err    string
+	}{
+		InvalidCharReplace: {
+			expect: "<param><value><string>a�b�c�d</string></value></param>" +
+				"<param><value><struct><member><name>k�</name><value><string>v</string></value></member></struct></value></param>",
+		},
+		InvalidCharStrip: {
+			expect: "<param><value><string>abcd</string></value></param>" +
+				"<param><value><struct><member><name>k</name><value><string>v</string></value></member></struct></value></param>",
+		},
+		InvalidCharReject: {
+			err: "cannot encode string value: failed to escape string: character U+0000 at offset 1 cannot be represented in XML",
+		},
+	}
+
+	for policy, tt := range tests {
+		t.Run(policy.String(), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := (&StdEncoder{invalidChars: policy}).Encode(buf, "test", args)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "<methodCall><methodName>test</methodName><params>"+tt.expect+"</params></methodCall>", buf.String())
+		})
+	}
+}
+
+func TestInvalidCharPolicy_sanitize(t *testing.T) {
+	tests := []struct {
+		in     string
+		strip  string
+		reject string
+	}{
+		{in: "plain text, ünïcödé & tabs\t\r\n", strip: "plain text, ünïcödé & tabs\t\r\n"},
+		{in: "\x01start", strip: "start", reject: "character U+0001 at offset 0 cannot be represented in XML"},
+		{in: "end￾", strip: "end", reject: "character U+FFFE at offset 3 cannot be represented in XML"},
+		{in: "bad\xc3utf8", strip: "badutf8", reject: "invalid UTF-8 at offset 3"},
+		{in: "emoji 😀", strip: "emoji 😀"},
+	}
+
+	for _, tt := range tests {
+		stripped, err := InvalidCharStrip.sanitize(tt.in)
+		require.NoError(t, err)
+		require.Equal(t, tt.strip, stripped)
+
+		rejected, err := InvalidCharReject.sanitize(tt.in)
+		if tt.reject != "" {
+			require.EqualError(t, err, tt.reject)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tt.in, rejected)
+	}
+}
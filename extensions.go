@@ -0,0 +1,149 @@
+package xmlrpc
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ResponseDOM holds the contents of an <ex:dom> value, which is an XML document node.
+type ResponseDOM struct {
+	XML string `xml:",innerxml"`
+}
+
+// extensionDateTimeLayouts are accepted layouts of <ex:dateTime> values, which are xs:dateTime.
+var extensionDateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// isExtension reports whether value is of a vendor extension type decoded by decodeExtension.
+func (value *ResponseValue) isExtension() bool {
+	return value.Int1 != nil || value.Int2 != nil || value.Int8 != nil || value.Float != nil ||
+		value.BigInteger != nil || value.BigDecimal != nil || value.ExtDateTime != nil ||
+		value.DOM != nil || value.Serializable != nil
+}
+
+// decodeExtension decodes a value of a vendor extension type into the Go type matching it.
+// Big numbers are decoded into *big.Int and *big.Float, or kept as decimal strings when decoded into a string.
+func (d *StdDecoder) decodeExtension(value *ResponseValue, field reflect.Value) (interface{}, error) {
+	switch {
+	case value.Int1 != nil:
+		return parseInt[int8](*value.Int1, 8)
+	case value.Int2 != nil:
+		return parseInt[int16](*value.Int2, 16)
+	case value.Int8 != nil:
+		return parseInt[int64](*value.Int8, 64)
+
+	case value.Float != nil:
+		if *value.Float == "" {
+			return float32(0), nil
+		}
+		f, err := strconv.ParseFloat(*value.Float, 32)
+		return float32(f), err
+
+	case value.BigInteger != nil:
+		if field.Kind() == reflect.String {
+			return *value.BigInteger, nil
+		}
+		i, ok := new(big.Int).SetString(*value.BigInteger, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid biginteger '%s'", *value.BigInteger)
+		}
+		return bigValue(field, i), nil
+
+	case value.BigDecimal != nil:
+		if field.Kind() == reflect.String {
+			return *value.BigDecimal, nil
+		}
+		f, _, err := big.ParseFloat(*value.BigDecimal, 10, 0, big.ToNearestEven)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bigdecimal '%s': %w", *value.BigDecimal, err)
+		}
+		return bigValue(field, f), nil
+
+	case value.ExtDateTime != nil:
+		if *value.ExtDateTime == "" {
+			return time.Time{}, nil
+		}
+		for _, layout := range extensionDateTimeLayouts {
+			if t, err := time.Parse(layout, *value.ExtDateTime); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid dateTime '%s'", *value.ExtDateTime)
+
+	case value.DOM != nil:
+		return value.DOM.XML, nil
+
+	default:
+		return d.decodeBase64(*value.Serializable)
+	}
+}
+
+func parseInt[T int8 | int16 | int64](text string, bitSize int) (T, error) {
+	if text == "" {
+		return 0, nil
+	}
+	i, err := strconv.ParseInt(text, 10, bitSize)
+	return T(i), err
+}
+
+// bigValue returns a big number to be assigned to field: the pointer for interfaces, otherwise the number it points to,
+// as pointer fields are dereferenced before values are assigned.
+func bigValue[T big.Int | big.Float](field reflect.Value, x *T) interface{} {
+	if field.Kind() == reflect.Interface {
+		return x
+	}
+
+	return *x
+}
+
+// extensionTags are elements of vendor extension types numbers of Go kinds are encoded as.
+var extensionTags = map[reflect.Kind]string{
+	reflect.Int8:    "ex:i1",
+	reflect.Int16:   "ex:i2",
+	reflect.Int64:   "ex:i8",
+	reflect.Float32: "ex:float",
+}
+
+// encodeExtensionNumber writes a number of a kind only vendor extension types represent, e.g. int8 as <ex:i1>.
+func (e *StdEncoder) encodeExtensionNumber(w io.Writer, v reflect.Value) error {
+	tag, ok := extensionTags[v.Kind()]
+	if !ok || !e.dialect.EmitExtensions {
+		return fmt.Errorf("unsupported type %v", v.Kind())
+	}
+
+	if v.Kind() == reflect.Float32 {
+		_, _ = fmt.Fprintf(w, "<%s>%s</%s>", tag, strconv.FormatFloat(v.Float(), 'f', -1, 32), tag)
+	} else {
+		_, _ = fmt.Fprintf(w, "<%s>%d</%s>", tag, v.Int(), tag)
+	}
+
+	return nil
+}
+
+// encodeBigNumber writes non-nil *big.Int and *big.Float values as <ex:biginteger> and <ex:bigdecimal>.
+// It reports whether the value is such a number.
+func (e *StdEncoder) encodeBigNumber(w io.Writer, value interface{}) bool {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return false
+		}
+		_, _ = fmt.Fprintf(w, "<value><ex:biginteger>%s</ex:biginteger></value>", v.String())
+	case *big.Float:
+		if v == nil {
+			return false
+		}
+		_, _ = fmt.Fprintf(w, "<value><ex:bigdecimal>%s</ex:bigdecimal></value>", v.Text('f', -1))
+	default:
+		return false
+	}
+
+	return true
+}
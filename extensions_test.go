@@ -0,0 +1,175 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdDecoder_Decode_Extensions(t *testing.T) {
+	body := `<?xml version="1.0"?><methodResponse xmlns:ex="http://ws.apache.org/xmlrpc/namespaces/extensions"><params><param><value><struct>` +
+		`<member><name>tiny</name><value><ex:i1>-128</ex:i1></value></member>` +
+		`<member><name>small</name><value><ex:i2>32767</ex:i2></value></member>` +
+		`<member><name>large</name><value><ex:i8>-9007199254740993</ex:i8></value></member>` +
+		`<member><name>ratio</name><value><ex:float>0.25</ex:float></value></member>` +
+		`<member><name>count</name><value><ex:biginteger>123456789012345678901234567890</ex:biginteger></value></member>` +
+		`<member><name>count_text</name><value><ex:biginteger>123456789012345678901234567890</ex:biginteger></value></member>` +
+		`<member><name>price</name><value><ex:bigdecimal>1234.5678</ex:bigdecimal></value></member>` +
+		`<member><name>price_text</name><value><ex:bigdecimal>0.10000000000000000001</ex:bigdecimal></value></member>` +
+		`<member><name>created</name><value><ex:dateTime>2024-05-17T09:30:00.250+02:00</ex:dateTime></value></member>` +
+		`<member><name>document</name><value><ex:dom><note lang="en">Hi &amp; bye</note></ex:dom></value></member>` +
+		`</struct></value></param></params></methodResponse>`
+
+	type record struct {
+		Tiny      int8
+		Small     int16
+		Large     int64
+		Ratio     float32
+		Count     *big.Int
+		CountText string
+		Price     big.Float
+		PriceText string
+		Created   time.Time
+		Document  string
+	}
+
+	zone := time.FixedZone("", 2*60*60)
+	expectPrice, _, err := big.ParseFloat("1234.5678", 10, 0, big.ToNearestEven)
+	require.NoError(t, err)
+	expectCount, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	dec := &StdDecoder{dialect: DialectApache}
+	for mode, decode := range map[string]func(v any) error{
+		"DecodeRaw":    func(v any) error { return dec.DecodeRaw([]byte(body), v) },
+		"DecodeStream": func(v any) error { return dec.DecodeStream(strings.NewReader(body), v) },
+	} {
+		v := &record{}
+		require.NoError(t, decode(v), mode)
+		require.Equal(t, int8(-128), v.Tiny, mode)
+		require.Equal(t, int16(32767), v.Small, mode)
+		require.Equal(t, int64(-9007199254740993), v.Large, mode)
+		require.Equal(t, float32(0.25), v.Ratio, mode)
+		require.Equal(t, 0, expectCount.Cmp(v.Count), mode)
+		require.Equal(t, "123456789012345678901234567890", v.CountText, mode)
+		require.Equal(t, 0, expectPrice.Cmp(&v.Price), mode)
+		require.Equal(t, "0.10000000000000000001", v.PriceText, mode)
+		require.True(t, time.Date(2024, 5, 17, 9, 30, 0, 250000000, zone).Equal(v.Created), mode)
+		require.Equal(t, `<note lang="en">Hi &amp; bye</note>`, v.Document, mode)
+
+		// Dynamic targets receive the matching Go types
+		var m map[string]any
+		require.NoError(t, decode(&m), mode)
+		require.IsType(t, int8(0), m["tiny"], mode)
+		require.IsType(t, int16(0), m["small"], mode)
+		require.IsType(t, float32(0), m["ratio"], mode)
+		require.IsType(t, &big.Int{}, m["count"], mode)
+		require.IsType(t, &big.Float{}, m["price"], mode)
+		require.IsType(t, time.Time{}, m["created"], mode)
+	}
+}
+
+func TestStdDecoder_Decode_Extensions_Errors(t *testing.T) {
+	tests := map[string]struct {
+		value string
+		v     any
+		err   string
+	}{
+		"i1 out of range": {
+			value: `<ex:i1>128</ex:i1>`,
+			v:     new(int8),
+			err:   "value out of range",
+		},
+		"invalid biginteger": {
+			value: `<ex:biginteger>12x</ex:biginteger>`,
+			v:     new(*big.Int),
+			err:   "invalid biginteger '12x'",
+		},
+		"invalid bigdecimal": {
+			value: `<ex:bigdecimal>1.2.3</ex:bigdecimal>`,
+			v:     new(*big.Float),
+			err:   "invalid bigdecimal '1.2.3'",
+		},
+		"invalid dateTime": {
+			value: `<ex:dateTime>yesterday</ex:dateTime>`,
+			v:     new(time.Time),
+			err:   "invalid dateTime 'yesterday'",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			body := `<methodResponse><params><param><value>` + tt.value + `</value></param></params></methodResponse>`
+			err := (&StdDecoder{dialect: DialectApache}).DecodeRaw([]byte(body), tt.v)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestStdDecoder_Decode_Extensions_SingleParam(t *testing.T) {
+	body := []byte(`<methodResponse xmlns:ex="http://ws.apache.org/xmlrpc/namespaces/extensions"><params><param><value>` +
+		`<ex:biginteger>123456789012345678901234567890</ex:biginteger></value></param></params></methodResponse>`)
+	expect, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	for _, lenient := range []bool{false, true} {
+		dec := &StdDecoder{dialect: DialectApache, lenientParams: lenient}
+
+		count := new(big.Int)
+		require.NoError(t, dec.DecodeRaw(body, count))
+		require.Zero(t, expect.Cmp(count))
+
+		count = new(big.Int)
+		require.NoError(t, dec.DecodeStream(bytes.NewReader(body), count))
+		require.Zero(t, expect.Cmp(count))
+	}
+}
+
+func TestStdEncoder_Encode_Extensions(t *testing.T) {
+	count, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	args := Args{int8(-1), int16(300), int64(1) << 40, float32(0.1), count, big.NewFloat(12.5), (*big.Int)(nil)}
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, (&StdEncoder{dialect: DialectApache}).Encode(buf, "m", args))
+	require.Equal(t, `<methodCall xmlns:ex="http://ws.apache.org/xmlrpc/namespaces/extensions"><methodName>m</methodName><params>`+
+		`<param><value><ex:i1>-1</ex:i1></value></param>`+
+		`<param><value><ex:i2>300</ex:i2></value></param>`+
+		`<param><value><ex:i8>1099511627776</ex:i8></value></param>`+
+		`<param><value><ex:float>0.1</ex:float></value></param>`+
+		`<param><value><ex:biginteger>123456789012345678901234567890</ex:biginteger></value></param>`+
+		`<param><value><ex:bigdecimal>12.5</ex:bigdecimal></value></param>`+
+		`<param><value><ex:nil/></value></param>`+
+		`</params></methodCall>`, buf.String())
+
+	// Round trip
+	type values struct {
+		_     struct{} `xmlrpc:",tuple"`
+		Tiny  int8
+		Small int16
+		Large int64
+		Ratio float32
+		Count *big.Int
+		Price *big.Float
+		Nil   *big.Int
+	}
+	buf.Reset()
+	require.NoError(t, (&StdEncoder{dialect: DialectApache}).encodeResponse(buf, []any(args), true))
+
+	v := &values{}
+	require.NoError(t, (&StdDecoder{dialect: DialectApache}).DecodeRaw(buf.Bytes(), v))
+	require.Equal(t, int8(-1), v.Tiny)
+	require.Equal(t, int16(300), v.Small)
+	require.Equal(t, int64(1)<<40, v.Large)
+	require.Equal(t, float32(0.1), v.Ratio)
+	require.Equal(t, 0, count.Cmp(v.Count))
+	require.Equal(t, 0, big.NewFloat(12.5).Cmp(v.Price))
+	require.Nil(t, v.Nil)
+
+	// Without extensions, these types are not supported
+	for _, arg := range []any{int8(1), int16(1), float32(1)} {
+		err := (&StdEncoder{dialect: DialectPython}).Encode(new(bytes.Buffer), "m", Args{arg})
+		require.ErrorContains(t, err, "unsupported type")
+	}
+}
@@ -2,6 +2,17 @@ package xmlrpc
 
 import "fmt"
 
+// Fault codes used by Server, following the specification for fault code interoperability
+// (http://xmlrpc-epi.sourceforge.net/specs/rfc.fault_codes.php).
+const (
+	FaultParseError       = -32700
+	FaultInvalidRequest   = -32600
+	FaultMethodNotFound   = -32601
+	FaultInvalidParams    = -32602
+	FaultInternalError    = -32603
+	FaultApplicationError = -32500
+)
+
 // Fault is a wrapper for XML-RPC fault object
 type Fault struct {
 	// Code provides numerical failure code
@@ -0,0 +1,36 @@
+// Package naming holds conversions of XML-RPC names shared by the xmlrpc package and code generation.
+package naming
+
+import "strings"
+
+// FieldName returns the name of a Go struct field that a <struct> member with provided name is decoded into,
+// unless the field is remapped with `xmlrpc` tag.
+func FieldName(memberName string) string {
+	b := new(strings.Builder)
+	capNext := true
+
+	for _, v := range memberName {
+		if v >= 'A' && v <= 'Z' {
+			b.WriteRune(v)
+		}
+		if v >= '0' && v <= '9' {
+			b.WriteRune(v)
+		}
+
+		if v >= 'a' && v <= 'z' {
+			if capNext {
+				b.WriteString(strings.ToUpper(string(v)))
+			} else {
+				b.WriteRune(v)
+			}
+		}
+
+		if v == '_' || v == ' ' || v == '-' || v == '.' {
+			capNext = true
+		} else {
+			capNext = false
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,43 @@
+package xmlrpc
+
+import "context"
+
+// Invoke calls the remote method with provided arguments and returns the reply decoded into a new value of type Resp.
+// Arguments follow the same rules as in Client.Call (e.g. a struct, a map or Args), while Resp may be any type accepted by StdDecoder.
+//
+// Errors are returned as-is, e.g. a fault response is returned as *Fault.
+func Invoke[Resp any](ctx context.Context, client *Client, method string, args any) (Resp, error) {
+	var resp Resp
+	if err := client.CallContext(ctx, method, args, &resp); err != nil {
+		var zero Resp
+		return zero, err
+	}
+
+	return resp, nil
+}
+
+// Method is a typed handle of a remote method, which can be stored and reused for multiple calls.
+// Both arguments and reply types are checked at compile time.
+type Method[Req, Resp any] struct {
+	client *Client
+	name   string
+}
+
+// NewMethod creates a typed handle of a remote method with provided name.
+// Use Args as Req type for positional arguments, or struct{} if method takes no arguments.
+func NewMethod[Req, Resp any](client *Client, name string) *Method[Req, Resp] {
+	return &Method[Req, Resp]{
+		client: client,
+		name:   name,
+	}
+}
+
+// Name returns the name of the remote method.
+func (m *Method[Req, Resp]) Name() string {
+	return m.name
+}
+
+// Call invokes the remote method with provided arguments and returns a decoded reply.
+func (m *Method[Req, Resp]) Call(ctx context.Context, req Req) (Resp, error) {
+	return Invoke[Resp](ctx, m.client, m.name, req)
+}
@@ -0,0 +1,125 @@
+package xmlrpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvoke(t *testing.T) {
+	ts := mockupServer(t, "response_bugzilla_version.xml")
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL)
+	require.NoError(t, err)
+	defer c.Close()
+
+	type Version struct {
+		Version string
+	}
+
+	resp, err := Invoke[struct{ BugzillaVersion Version }](context.Background(), c, "Bugzilla.version", nil)
+	require.NoError(t, err)
+	require.Equal(t, "20220802.1", resp.BugzillaVersion.Version)
+
+	m, err := Invoke[map[string]string](context.Background(), c, "Bugzilla.version", nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"version": "20220802.1"}, m)
+}
+
+func TestInvoke_Errors(t *testing.T) {
+	t.Run("fault", func(t *testing.T) {
+		ts := mockupServer(t, "response_fault.xml")
+		defer ts.Close()
+
+		c, err := NewClient(ts.URL)
+		require.NoError(t, err)
+		defer c.Close()
+
+		_, err = Invoke[string](context.Background(), c, "my.fault", nil)
+		require.Error(t, err)
+
+		fT := &Fault{}
+		require.True(t, errors.As(err, &fT))
+		require.Equal(t, &Fault{Code: 4, String: "Too many parameters."}, fT)
+	})
+
+	t.Run("decoding", func(t *testing.T) {
+		ts := mockupServer(t, "response_simple.xml")
+		defer ts.Close()
+
+		c, err := NewClient(ts.URL)
+		require.NoError(t, err)
+		defer c.Close()
+
+		_, err = Invoke[string](context.Background(), c, "my.simple", nil)
+		require.EqualError(t, err, "number of params (2) doesnt match expectation (1) of response type *string")
+
+		// Client remains usable after a decoding failure
+		params, err := Invoke[[]any](context.Background(), c, "my.simple", nil)
+		require.NoError(t, err)
+		require.Equal(t, []any{"South Dakota", 12345}, params)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ts := mockupServer(t, "response_simple.xml")
+		defer ts.Close()
+
+		c, err := NewClient(ts.URL)
+		require.NoError(t, err)
+		defer c.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err = Invoke[[]any](ctx, c, "my.simple", nil)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("context deadline during request", func(t *testing.T) {
+		release := make(chan struct{})
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer ts.Close()
+		defer close(release)
+
+		c, err := NewClient(ts.URL)
+		require.NoError(t, err)
+		defer c.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err = Invoke[[]any](ctx, c, "my.slow", nil)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestNewMethod(t *testing.T) {
+	ts := mockupServer(t, "response_simple.xml")
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL)
+	require.NoError(t, err)
+	defer c.Close()
+
+	type Location struct {
+		Area  string
+		Index int
+	}
+
+	m := NewMethod[Args, Location](c, "my.simple")
+	require.Equal(t, "my.simple", m.Name())
+
+	for i := 0; i < 2; i++ {
+		resp, err := m.Call(context.Background(), Args{12345})
+		require.NoError(t, err)
+		require.Equal(t, Location{Area: "South Dakota", Index: 12345}, resp)
+	}
+}
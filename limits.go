@@ -0,0 +1,229 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"golang.org/x/net/html/charset"
+)
+
+// Limit identifies a resource limit of response parsing.
+type Limit int
+
+const (
+	// LimitResponseSize is the limit of response body size in bytes, see MaxResponseSize.
+	LimitResponseSize Limit = iota + 1
+	// LimitDepth is the limit of nesting of arrays and structs, see MaxDepth.
+	LimitDepth
+	// LimitArrayLength is the limit of number of array elements, see MaxArrayLength.
+	LimitArrayLength
+	// LimitStructMembers is the limit of number of struct members, see MaxStructMembers.
+	LimitStructMembers
+	// LimitStringSize is the limit of string size in bytes, see MaxStringSize.
+	LimitStringSize
+	// LimitBase64Size is the limit of base64 encoded data size in bytes, see MaxBase64Size.
+	LimitBase64Size
+)
+
+var limitNames = map[Limit]string{
+	LimitResponseSize:  "response size",
+	LimitDepth:         "depth",
+	LimitArrayLength:   "array length",
+	LimitStructMembers: "struct members",
+	LimitStringSize:    "string size",
+	LimitBase64Size:    "base64 size",
+}
+
+func (l Limit) String() string {
+	if name, ok := limitNames[l]; ok {
+		return name
+	}
+
+	return "limit(" + strconv.Itoa(int(l)) + ")"
+}
+
+// LimitError is returned when a response exceeds one of the configured limits.
+type LimitError struct {
+	Limit Limit
+	Max   int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("response exceeds %s limit of %d", e.Limit, e.Max)
+}
+
+// limits of response parsing, zero values mean unlimited.
+type limits struct {
+	responseSize  int64
+	depth         int
+	arrayLength   int
+	structMembers int
+	stringSize    int
+	base64Size    int
+}
+
+// readBody reads the response body, failing once it exceeds the response size limit.
+func (l *limits) readBody(r io.Reader) ([]byte, error) {
+	if l.responseSize <= 0 {
+		return io.ReadAll(r)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r, l.responseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > l.responseSize {
+		return nil, &LimitError{Limit: LimitResponseSize, Max: l.responseSize}
+	}
+
+	return body, nil
+}
+
+// hasStructural reports whether any limits of the document structure are set.
+func (l *limits) hasStructural() bool {
+	return l.depth > 0 || l.arrayLength > 0 || l.structMembers > 0 || l.stringSize > 0 || l.base64Size > 0
+}
+
+// check scans tokens of the body, before it is decoded into a tree, and returns LimitError for the first exceeded limit.
+// Errors of the XML syntax are left to be reported by decoding.
+func (l *limits) check(body []byte) error {
+	if !l.hasStructural() {
+		return nil
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	dec.CharsetReader = charset.NewReaderLabel
+
+	scanner := &limitScanner{limits: l}
+	for {
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return nil //nolint:nilerr // reported by decoding
+		}
+
+		if err := scanner.token(token); err != nil {
+			return err
+		}
+	}
+}
+
+// reader limits r to the response size limit, failing with LimitError once it is exceeded.
+func (l *limits) reader(r io.Reader) io.Reader {
+	if l.responseSize <= 0 {
+		return r
+	}
+
+	return &limitedReader{r: r, remaining: l.responseSize, max: l.responseSize}
+}
+
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	max       int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		// Anything beyond the limit exceeds it, but the body may end just there
+		var b [1]byte
+		if n, err := r.r.Read(b[:]); n == 0 {
+			return 0, err
+		}
+		return 0, &LimitError{Limit: LimitResponseSize, Max: r.max}
+	}
+
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.r.Read(p)
+	r.remaining -= int64(n)
+
+	return n, err
+}
+
+// limitScanner checks tokens of a document against structural limits, one token at a time.
+type limitScanner struct {
+	limits *limits
+	stack  []scannedElement
+	depth  int
+}
+
+// scannedElement is an open element, counting its children of interest or size of its text.
+type scannedElement struct {
+	name  string
+	count int
+}
+
+func (s *limitScanner) token(token xml.Token) error {
+	l := s.limits
+
+	switch t := token.(type) {
+	case xml.StartElement:
+		name := t.Name.Local
+		if len(s.stack) > 0 {
+			parent := &s.stack[len(s.stack)-1]
+			switch {
+			case parent.name == "data" && name == "value":
+				parent.count++
+				if exceeds(parent.count, l.arrayLength) {
+					return &LimitError{Limit: LimitArrayLength, Max: int64(l.arrayLength)}
+				}
+			case parent.name == "struct" && name == "member":
+				parent.count++
+				if exceeds(parent.count, l.structMembers) {
+					return &LimitError{Limit: LimitStructMembers, Max: int64(l.structMembers)}
+				}
+			}
+		}
+
+		if name == "array" || name == "struct" {
+			s.depth++
+			if exceeds(s.depth, l.depth) {
+				return &LimitError{Limit: LimitDepth, Max: int64(l.depth)}
+			}
+		}
+
+		s.stack = append(s.stack, scannedElement{name: name})
+
+	case xml.EndElement:
+		if len(s.stack) > 0 {
+			s.stack = s.stack[:len(s.stack)-1]
+		}
+		if name := t.Name.Local; name == "array" || name == "struct" {
+			s.depth--
+		}
+
+	case xml.CharData:
+		if len(s.stack) == 0 {
+			return nil
+		}
+
+		current := &s.stack[len(s.stack)-1]
+		switch current.name {
+		case "string", "value":
+			current.count += len(t)
+			if exceeds(current.count, l.stringSize) {
+				return &LimitError{Limit: LimitStringSize, Max: int64(l.stringSize)}
+			}
+		case "base64":
+			current.count += len(t)
+			if exceeds(current.count, l.base64Size) {
+				return &LimitError{Limit: LimitBase64Size, Max: int64(l.base64Size)}
+			}
+		}
+	}
+
+	return nil
+}
+
+// exceeds reports whether n is over the limit, if set.
+func exceeds(n, limit int) bool {
+	return limit > 0 && n > limit
+}
@@ -0,0 +1,111 @@
+package xmlrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Limits(t *testing.T) {
+	nested := `<methodResponse><params><param><value><array><data><value><struct><member><name>a</name><value><array><data><value>x</value></data></array></value></member></struct></value></data></array></value></param></params></methodResponse>`
+
+	tests := []struct {
+		name string
+		body string
+		opts []Option
+		err  *LimitError
+	}{
+		{
+			name: "within all limits",
+			body: nested,
+			opts: []Option{MaxResponseSize(int64(len(nested))), MaxDepth(3), MaxArrayLength(1), MaxStructMembers(1), MaxStringSize(1)},
+		},
+		{
+			name: "response size",
+			body: nested,
+			opts: []Option{MaxResponseSize(int64(len(nested) - 1))},
+			err:  &LimitError{Limit: LimitResponseSize, Max: int64(len(nested) - 1)},
+		},
+		{
+			name: "depth",
+			body: nested,
+			opts: []Option{MaxDepth(2)},
+			err:  &LimitError{Limit: LimitDepth, Max: 2},
+		},
+		{
+			name: "array length",
+			body: string(loadTestFile(t, "response_array.xml")),
+			opts: []Option{MaxArrayLength(2)},
+			err:  &LimitError{Limit: LimitArrayLength, Max: 2},
+		},
+		{
+			name: "struct members",
+			body: string(loadTestFile(t, "response_struct.xml")),
+			opts: []Option{MaxStructMembers(5)},
+			err:  &LimitError{Limit: LimitStructMembers, Max: 5},
+		},
+		{
+			name: "string size",
+			body: `<methodResponse><params><param><value><string>` + strings.Repeat("a", 11) + `</string></value></param></params></methodResponse>`,
+			opts: []Option{MaxStringSize(10)},
+			err:  &LimitError{Limit: LimitStringSize, Max: 10},
+		},
+		{
+			name: "untyped string size",
+			body: `<methodResponse><params><param><value>` + strings.Repeat("a", 11) + `</value></param></params></methodResponse>`,
+			opts: []Option{MaxStringSize(10)},
+			err:  &LimitError{Limit: LimitStringSize, Max: 10},
+		},
+		{
+			name: "base64 size",
+			body: `<methodResponse><params><param><value><base64>` + strings.Repeat("YWFh", 3) + `</base64></value></param></params></methodResponse>`,
+			opts: []Option{MaxBase64Size(8), MaxStringSize(8)},
+			err:  &LimitError{Limit: LimitBase64Size, Max: 8},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, stream := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s stream=%t", tt.name, stream), func(t *testing.T) {
+				testClientLimit(t, tt.body, append(tt.opts, StreamResponses(stream)), tt.err)
+			})
+		}
+	}
+}
+
+func testClientLimit(t *testing.T, body string, opts []Option, expected *LimitError) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, body)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, opts...)
+	require.NoError(t, err)
+	defer c.Close()
+
+	var result any
+	err = c.CallContext(context.Background(), "test.Method", nil, &result)
+	if expected == nil {
+		require.NoError(t, err)
+		return
+	}
+
+	var limitErr *LimitError
+	require.True(t, errors.As(err, &limitErr), "expected LimitError, got %v", err)
+	require.Equal(t, expected, limitErr)
+
+	// Client keeps working after exceeding a limit
+	err = c.Call("test.Method", nil, &result)
+	require.EqualError(t, err, expected.Error())
+}
+
+func TestLimitError(t *testing.T) {
+	require.EqualError(t, &LimitError{Limit: LimitDepth, Max: 10}, "response exceeds depth limit of 10")
+	require.Equal(t, "limit(42)", Limit(42).String())
+}
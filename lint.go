@@ -0,0 +1,614 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// Issue is a violation of the XML-RPC specification found by Lint.
+type Issue struct {
+	// Line is the line of the offending element in the document.
+	Line int
+	// Path locates the offending value, using the same notation as Value.Path (e.g. "params.0.bugs.1.id").
+	// It is empty for issues of the document structure outside of values.
+	Path    string
+	Message string
+}
+
+func (i Issue) String() string {
+	if i.Path == "" {
+		return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", i.Line, i.Path, i.Message)
+}
+
+// dateTimeLayouts are accepted layouts of <dateTime.iso8601> values.
+var dateTimeLayouts = []string{
+	"20060102T15:04:05",
+	"20060102T15:04:05Z07:00",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+}
+
+// Lint parses a <methodCall> or <methodResponse> document and reports violations of the XML-RPC specification,
+// such as values with several type elements, <array> without <data>, faults without faultCode or faultString,
+// invalid method names or out of range <int> values.
+//
+// Error is returned only if the document is not well-formed XML, or is neither a method call nor a response.
+func Lint(body []byte) ([]Issue, error) {
+	root, err := parseNode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &linter{}
+	switch root.name {
+	case "methodCall":
+		l.methodCall(root)
+	case "methodResponse":
+		l.methodResponse(root)
+	}
+
+	return l.issues, nil
+}
+
+// Format parses a <methodCall> or <methodResponse> document and returns it in a canonical form:
+// UTF-8 encoded, indented by two spaces, with each scalar value on a single line and whitespace around
+// non-string scalars removed. Documents violating the specification are formatted as well, as far as they are well-formed XML.
+func Format(body []byte) ([]byte, error) {
+	root, err := parseNode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(xml.Header)
+	if err := root.format(buf, 0); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// node is an element of a parsed document.
+type node struct {
+	// name is the name of the element as written in the document, including the namespace prefix (e.g. "ex:i8")
+	name string
+	// space and local are the namespace (resolved from the prefix) and the local name of the element
+	space    string
+	local    string
+	attrs    []xml.Attr
+	line     int
+	children []*node
+	text     string
+}
+
+// parseNode parses a document into a tree of elements, ignoring comments and processing instructions.
+func parseNode(body []byte) (*node, error) {
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	dec.CharsetReader = charset.NewReaderLabel
+
+	var root *node
+	var stack []*node
+	// prefixes holds namespace prefixes declared by open elements, by namespace
+	var prefixes []map[string]string
+	prefixOf := func(space string) string {
+		for i := len(prefixes) - 1; i >= 0; i-- {
+			if prefix, ok := prefixes[i][space]; ok {
+				return prefix
+			}
+		}
+		// Undeclared prefixes are kept as the namespace by the decoder
+		return space
+	}
+	qualified := func(name xml.Name) string {
+		if prefix := prefixOf(name.Space); name.Space != "" && prefix != "" {
+			return prefix + ":" + name.Local
+		}
+		return name.Local
+	}
+
+	for {
+		line, _ := dec.InputPos()
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			declared := make(map[string]string)
+			for _, a := range t.Attr {
+				switch {
+				case a.Name.Space == "xmlns":
+					declared[a.Value] = a.Name.Local
+				case a.Name.Space == "" && a.Name.Local == "xmlns":
+					declared[a.Value] = ""
+				}
+			}
+			prefixes = append(prefixes, declared)
+
+			n := &node{name: qualified(t.Name), space: t.Name.Space, local: t.Name.Local, line: line}
+			for _, a := range t.Attr {
+				if a.Name.Space != "xmlns" && a.Name.Space != "" {
+					a.Name = xml.Name{Local: qualified(a.Name)}
+				}
+				n.attrs = append(n.attrs, a)
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			} else if root == nil {
+				root = n
+			}
+			stack = append(stack, n)
+
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+			prefixes = prefixes[:len(prefixes)-1]
+
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("document has no root element")
+	}
+	if root.name != "methodCall" && root.name != "methodResponse" {
+		return nil, fmt.Errorf("unexpected root element <%s>, expected <methodCall> or <methodResponse>", root.name)
+	}
+
+	return root, nil
+}
+
+// childrenNamed returns children with the given name.
+func (n *node) childrenNamed(name string) []*node {
+	var out []*node
+	for _, c := range n.children {
+		if c.name == name {
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+// scalarTypes are value type elements containing text.
+var scalarTypes = map[string]bool{
+	"i4":               true,
+	"int":              true,
+	"boolean":          true,
+	"string":           true,
+	"double":           true,
+	"dateTime.iso8601": true,
+	"base64":           true,
+}
+
+// preservesSpace reports whether whitespace of the element text is significant.
+func (n *node) preservesSpace() bool {
+	return n.name == "string" || n.name == "value" || n.name == "name" || n.name == "methodName"
+}
+
+func (n *node) format(w *bytes.Buffer, depth int) error {
+	indent := strings.Repeat("  ", depth)
+	w.WriteString(indent)
+
+	if err := n.formatInline(w); err == nil {
+		w.WriteByte('\n')
+		return nil
+	}
+
+	n.formatStart(w)
+	w.WriteByte('\n')
+	for _, c := range n.children {
+		if err := c.format(w, depth+1); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "%s</%s>\n", indent, n.name)
+
+	return nil
+}
+
+// formatStart writes the start tag of the element with its attributes (including namespace declarations), as written in the document.
+func (n *node) formatStart(w *bytes.Buffer) {
+	w.WriteString("<" + n.name)
+	for _, a := range n.attrs {
+		name := a.Name.Local
+		if a.Name.Space == "xmlns" {
+			name = "xmlns:" + name
+		}
+		w.WriteString(" " + name + `="`)
+		_ = xml.EscapeText(w, []byte(a.Value))
+		w.WriteString(`"`)
+	}
+	w.WriteString(">")
+}
+
+// errNotInline is returned by formatInline for elements which are not written on a single line.
+var errNotInline = errors.New("element is not inline")
+
+// formatInline writes elements without children, and values holding such an element, on a single line.
+func (n *node) formatInline(w *bytes.Buffer) error {
+	switch {
+	case len(n.children) == 0:
+		text := n.text
+		if !n.preservesSpace() {
+			text = strings.TrimSpace(text)
+		}
+		if text == "" && !n.preservesSpace() {
+			n.formatStart(w)
+			w.Truncate(w.Len() - 1)
+			w.WriteString("/>")
+			return nil
+		}
+
+		n.formatStart(w)
+		if err := xml.EscapeText(w, []byte(text)); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "</%s>", n.name)
+
+		return nil
+
+	case n.name == "value" && len(n.children) == 1 && len(n.children[0].children) == 0:
+		n.formatStart(w)
+		if err := n.children[0].formatInline(w); err != nil {
+			return err
+		}
+		w.WriteString("</value>")
+
+		return nil
+
+	default:
+		return errNotInline
+	}
+}
+
+// linter collects issues found in a document.
+type linter struct {
+	issues []Issue
+}
+
+func (l *linter) report(n *node, path string, format string, args ...any) {
+	l.issues = append(l.issues, Issue{Line: n.line, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// unexpected reports children other than the allowed ones.
+func (l *linter) unexpected(n *node, path string, allowed ...string) {
+	for _, c := range n.children {
+		ok := false
+		for _, a := range allowed {
+			ok = ok || c.name == a
+		}
+		if !ok {
+			l.report(c, path, "unexpected element <%s> in <%s>", c.name, n.name)
+		}
+	}
+}
+
+func (l *linter) methodCall(n *node) {
+	l.unexpected(n, "", "methodName", "params")
+
+	names := n.childrenNamed("methodName")
+	switch {
+	case len(names) == 0:
+		l.report(n, "", "missing <methodName>")
+	case len(names) > 1:
+		l.report(names[1], "", "multiple <methodName> elements")
+	}
+	for _, name := range names {
+		if !methodNamePattern.MatchString(name.text) {
+			l.report(name, "", "invalid method name '%s', only letters, digits and _ . : / are allowed", name.text)
+		}
+	}
+
+	params := n.childrenNamed("params")
+	if len(params) > 1 {
+		l.report(params[1], "", "multiple <params> elements")
+	}
+	for _, p := range params {
+		l.params(p)
+	}
+}
+
+func (l *linter) methodResponse(n *node) {
+	l.unexpected(n, "", "params", "fault")
+
+	params, faults := n.childrenNamed("params"), n.childrenNamed("fault")
+	switch {
+	case len(params)+len(faults) == 0:
+		l.report(n, "", "missing <params> or <fault>")
+	case len(params)+len(faults) > 1:
+		l.report(n, "", "response must contain either a single <params> or a single <fault>")
+	}
+
+	for _, p := range params {
+		if count := len(p.childrenNamed("param")); count != 1 {
+			l.report(p, "", "response must contain exactly one param, got %d", count)
+		}
+		l.params(p)
+	}
+
+	for _, f := range faults {
+		l.fault(f)
+	}
+}
+
+func (l *linter) params(n *node) {
+	l.unexpected(n, "", "param")
+
+	for i, p := range n.childrenNamed("param") {
+		path := fmt.Sprintf("params.%d", i)
+		l.unexpected(p, path, "value")
+
+		values := p.childrenNamed("value")
+		if len(values) != 1 {
+			l.report(p, path, "param must contain exactly one <value>, got %d", len(values))
+		}
+		for _, v := range values {
+			l.value(v, path)
+		}
+	}
+}
+
+func (l *linter) fault(n *node) {
+	l.unexpected(n, "fault", "value")
+
+	values := n.childrenNamed("value")
+	if len(values) != 1 {
+		l.report(n, "fault", "fault must contain exactly one <value>, got %d", len(values))
+		return
+	}
+
+	v := values[0]
+	l.value(v, "fault")
+
+	if len(v.children) != 1 || v.children[0].name != "struct" {
+		l.report(v, "fault", "fault value must be a <struct>")
+		return
+	}
+
+	members := make(map[string]*node)
+	for _, m := range v.children[0].childrenNamed("member") {
+		if names, values := m.childrenNamed("name"), m.childrenNamed("value"); len(names) == 1 && len(values) == 1 {
+			members[names[0].text] = values[0]
+		}
+	}
+
+	for _, f := range []struct{ name, typ string }{{"faultCode", "int"}, {"faultString", "string"}} {
+		name, typ := f.name, f.typ
+		value, ok := members[name]
+		if !ok {
+			l.report(v, "fault", "fault is missing %s", name)
+			continue
+		}
+
+		actual := "string"
+		if len(value.children) == 1 {
+			actual = value.children[0].name
+		}
+		if actual == "i4" {
+			actual = "int"
+		}
+		if actual != typ {
+			l.report(value, "fault."+name, "%s must be <%s>, got <%s>", name, typ, actual)
+		}
+	}
+
+	if len(members) > 2 { //nolint:mnd // faultCode and faultString
+		l.report(v, "fault", "fault must contain only faultCode and faultString members")
+	}
+}
+
+func (l *linter) value(n *node, path string) {
+	if len(n.children) == 0 {
+		// Untyped values are strings
+		return
+	}
+
+	if strings.TrimSpace(n.text) != "" {
+		l.report(n, path, "value contains both text and a type element")
+	}
+
+	if len(n.children) > 1 {
+		names := make([]string, len(n.children))
+		for i, c := range n.children {
+			names[i] = "<" + c.name + ">"
+		}
+		l.report(n, path, "value must contain a single type element, got %s", strings.Join(names, ", "))
+	}
+
+	for _, t := range n.children {
+		switch {
+		case t.name == "array":
+			l.array(t, path)
+		case t.name == "struct":
+			l.structValue(t, path)
+		case t.name == "nil":
+			if len(t.children) > 0 || strings.TrimSpace(t.text) != "" {
+				l.report(t, path, "<nil/> must be empty")
+			}
+		case t.space == extensionsNamespace:
+			l.extension(t, path)
+		case scalarTypes[t.name]:
+			if len(t.children) > 0 {
+				l.report(t, path, "<%s> must not contain elements", t.name)
+				continue
+			}
+			if msg := checkScalar(t.name, t.text); msg != "" {
+				l.report(t, path, "%s", msg)
+			}
+		default:
+			l.report(t, path, "unknown type element <%s>", t.name)
+		}
+	}
+}
+
+func (l *linter) array(n *node, path string) {
+	l.unexpected(n, path, "data")
+
+	data := n.childrenNamed("data")
+	switch {
+	case len(data) == 0:
+		l.report(n, path, "<array> without <data>")
+		return
+	case len(data) > 1:
+		l.report(data[1], path, "<array> must contain a single <data>")
+	}
+
+	i := 0
+	for _, d := range data {
+		l.unexpected(d, path, "value")
+		for _, v := range d.childrenNamed("value") {
+			l.value(v, joinPath(path, strconv.Itoa(i)))
+			i++
+		}
+	}
+}
+
+func (l *linter) structValue(n *node, path string) {
+	l.unexpected(n, path, "member")
+
+	seen := make(map[string]bool)
+	for _, m := range n.childrenNamed("member") {
+		l.unexpected(m, path, "name", "value")
+
+		names, values := m.childrenNamed("name"), m.childrenNamed("value")
+		if len(names) != 1 {
+			l.report(m, path, "member must contain exactly one <name>, got %d", len(names))
+		}
+		if len(values) != 1 {
+			l.report(m, path, "member must contain exactly one <value>, got %d", len(values))
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		name := names[0].text
+		if seen[name] {
+			l.report(m, path, "duplicate member '%s'", name)
+		}
+		seen[name] = true
+
+		for _, v := range values {
+			l.value(v, joinPath(path, name))
+		}
+	}
+}
+
+// extension validates a value of Apache ws-xmlrpc vendor extension types, which are not part of the specification,
+// but are accepted when declared in their namespace.
+func (l *linter) extension(n *node, path string) {
+	switch n.local {
+	case "dom", "serializable":
+		return
+	case "nil":
+		if len(n.children) > 0 || strings.TrimSpace(n.text) != "" {
+			l.report(n, path, "<%s/> must be empty", n.name)
+		}
+		return
+	case "i1", "i2", "i8", "float", "biginteger", "bigdecimal", "dateTime":
+	default:
+		l.report(n, path, "unknown extension type element <%s>", n.name)
+		return
+	}
+
+	if len(n.children) > 0 {
+		l.report(n, path, "<%s> must not contain elements", n.name)
+		return
+	}
+
+	text := strings.TrimSpace(n.text)
+	var err error
+	switch n.local {
+	case "i1", "i2", "i8":
+		bits := map[string]int{"i1": 8, "i2": 16, "i8": 64}[n.local]
+		_, err = strconv.ParseInt(text, 10, bits)
+	case "float":
+		_, err = strconv.ParseFloat(text, 32)
+	case "biginteger":
+		if _, ok := new(big.Int).SetString(text, 10); !ok {
+			err = errors.New("invalid")
+		}
+	case "bigdecimal":
+		_, _, err = big.ParseFloat(text, 10, 0, big.ToNearestEven)
+	case "dateTime":
+		err = errors.New("invalid")
+		for _, layout := range extensionDateTimeLayouts {
+			if _, parseErr := time.Parse(layout, text); parseErr == nil {
+				err = nil
+				break
+			}
+		}
+	}
+	if err != nil {
+		l.report(n, path, "invalid <%s> value '%s'", n.name, text)
+	}
+}
+
+// checkScalar validates text of a scalar type element, returning description of the problem.
+func checkScalar(typ, text string) string {
+	text = strings.TrimSpace(text)
+
+	switch typ {
+	case "i4", "int":
+		i, err := strconv.ParseInt(text, 10, 64)
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return fmt.Sprintf("invalid <%s> value '%s'", typ, text)
+		}
+		if err != nil || i < math.MinInt32 || i > math.MaxInt32 {
+			return fmt.Sprintf("<%s> value %s overflows 32-bit integer", typ, text)
+		}
+
+	case "boolean":
+		if text != "0" && text != "1" {
+			return fmt.Sprintf("invalid <boolean> value '%s', expected 0 or 1", text)
+		}
+
+	case "double":
+		if _, err := strconv.ParseFloat(text, 64); err != nil {
+			return fmt.Sprintf("invalid <double> value '%s'", text)
+		}
+
+	case "dateTime.iso8601":
+		for _, layout := range dateTimeLayouts {
+			if _, err := time.Parse(layout, text); err == nil {
+				return ""
+			}
+		}
+		return fmt.Sprintf("invalid <dateTime.iso8601> value '%s'", text)
+
+	case "base64":
+		compact := strings.Join(strings.Fields(text), "")
+		if _, err := base64.StdEncoding.DecodeString(compact); err != nil {
+			return fmt.Sprintf("invalid <base64> value: %v", err)
+		}
+	}
+
+	return ""
+}
+
+func joinPath(path, elem string) string {
+	if path == "" {
+		return elem
+	}
+	return path + "." + elem
+}
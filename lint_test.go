@@ -0,0 +1,242 @@
+package xmlrpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    string
+		issues []string
+	}{
+		{
+			name: "valid call",
+			doc:  `<methodCall><methodName>system.listMethods</methodName><params><param><value><array><data><value>a</value></data></array></value></param></params></methodCall>`,
+		},
+		{
+			name: "valid fault",
+			doc:  `<methodResponse><fault><value><struct><member><name>faultCode</name><value><i4>4</i4></value></member><member><name>faultString</name><value>Too many params</value></member></struct></value></fault></methodResponse>`,
+		},
+		{
+			name:   "two type elements",
+			doc:    `<methodResponse><params><param><value><int>1</int><string>1</string></value></param></params></methodResponse>`,
+			issues: []string{"line 1: params.0: value must contain a single type element, got <int>, <string>"},
+		},
+		{
+			name:   "array without data",
+			doc:    "<methodResponse><params><param><value><struct>\n<member><name>ids</name><value><array><value><int>1</int></value></array></value></member>\n</struct></value></param></params></methodResponse>",
+			issues: []string{"line 2: params.0.ids: unexpected element <value> in <array>", "line 2: params.0.ids: <array> without <data>"},
+		},
+		{
+			name: "fault without code and string",
+			doc:  `<methodResponse><fault><value><struct><member><name>code</name><value><int>1</int></value></member></struct></value></fault></methodResponse>`,
+			issues: []string{
+				"line 1: fault: fault is missing faultCode",
+				"line 1: fault: fault is missing faultString",
+			},
+		},
+		{
+			name:   "fault code of wrong type",
+			doc:    `<methodResponse><fault><value><struct><member><name>faultCode</name><value><string>E1</string></value></member><member><name>faultString</name><value>x</value></member></struct></value></fault></methodResponse>`,
+			issues: []string{"line 1: fault.faultCode: faultCode must be <int>, got <string>"},
+		},
+		{
+			name:   "fault is not a struct",
+			doc:    `<methodResponse><fault><value><string>failed</string></value></fault></methodResponse>`,
+			issues: []string{"line 1: fault: fault value must be a <struct>"},
+		},
+		{
+			name: "valid extension types",
+			doc:  `<methodResponse xmlns:ex="http://ws.apache.org/xmlrpc/namespaces/extensions"><params><param><value><array><data><value><ex:i1>-128</ex:i1></value><value><ex:i8>9007199254740993</ex:i8></value><value><ex:biginteger>123456789012345678901234567890</ex:biginteger></value><value><ex:bigdecimal>1.25</ex:bigdecimal></value><value><ex:dateTime>2021-03-04T05:06:07.890+0100</ex:dateTime></value><value><ex:nil/></value></data></array></value></param></params></methodResponse>`,
+		},
+		{
+			name: "invalid extension types",
+			doc:  `<methodResponse xmlns:ex="http://ws.apache.org/xmlrpc/namespaces/extensions"><params><param><value><array><data><value><ex:i1>300</ex:i1></value><value><ex:long>1</ex:long></value></data></array></value></param></params></methodResponse>`,
+			issues: []string{
+				"line 1: params.0.0: invalid <ex:i1> value '300'",
+				"line 1: params.0.1: unknown extension type element <ex:long>",
+			},
+		},
+		{
+			name:   "extension type outside of its namespace",
+			doc:    `<methodResponse><params><param><value><i8>1</i8></value></param></params></methodResponse>`,
+			issues: []string{"line 1: params.0: unknown type element <i8>"},
+		},
+		{
+			name:   "invalid method name",
+			doc:    `<methodCall><methodName>get user</methodName></methodCall>`,
+			issues: []string{"line 1: invalid method name 'get user', only letters, digits and _ . : / are allowed"},
+		},
+		{
+			name:   "missing method name",
+			doc:    `<methodCall><params/></methodCall>`,
+			issues: []string{"line 1: missing <methodName>"},
+		},
+		{
+			name: "int overflow",
+			doc:  `<methodResponse><params><param><value><array><data><value><int>2147483648</int></value><value><i4>-99999999999999999999</i4></value><value><int>-2147483648</int></value></data></array></value></param></params></methodResponse>`,
+			issues: []string{
+				"line 1: params.0.0: <int> value 2147483648 overflows 32-bit integer",
+				"line 1: params.0.1: <i4> value -99999999999999999999 overflows 32-bit integer",
+			},
+		},
+		{
+			name: "invalid scalars",
+			doc: `<methodResponse><params><param><value><struct>
+<member><name>a</name><value><int>1.5</int></value></member>
+<member><name>b</name><value><boolean>true</boolean></value></member>
+<member><name>c</name><value><double>one</double></value></member>
+<member><name>d</name><value><dateTime.iso8601>yesterday</dateTime.iso8601></value></member>
+<member><name>e</name><value><dateTime.iso8601>19980717T14:08:55</dateTime.iso8601></value></member>
+<member><name>f</name><value><base64>!!</base64></value></member>
+<member><name>g</name><value><nil/></value></member>
+<member><name>g</name><value><int><i4>1</i4></int></value></member>
+<member><name>h</name><value>text<int>1</int></value></member>
+<member><name>i</name><value><long>1</long></value></member>
+<member><value>1</value></member>
+</struct></value></param></params></methodResponse>`,
+			issues: []string{
+				"line 2: params.0.a: invalid <int> value '1.5'",
+				"line 3: params.0.b: invalid <boolean> value 'true', expected 0 or 1",
+				"line 4: params.0.c: invalid <double> value 'one'",
+				"line 5: params.0.d: invalid <dateTime.iso8601> value 'yesterday'",
+				"line 7: params.0.f: invalid <base64> value: illegal base64 data at input byte 0",
+				"line 9: params.0: duplicate member 'g'",
+				"line 9: params.0.g: <int> must not contain elements",
+				"line 10: params.0.h: value contains both text and a type element",
+				"line 11: params.0.i: unknown type element <long>",
+				"line 12: params.0: member must contain exactly one <name>, got 0",
+			},
+		},
+		{
+			name: "response structure",
+			doc:  `<methodResponse><params><param><value>1</value></param><param><value>2</value><value>3</value></param></params><fault/></methodResponse>`,
+			issues: []string{
+				"line 1: response must contain either a single <params> or a single <fault>",
+				"line 1: response must contain exactly one param, got 2",
+				"line 1: params.1: param must contain exactly one <value>, got 2",
+				"line 1: fault: fault must contain exactly one <value>, got 0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := Lint([]byte(tt.doc))
+			require.NoError(t, err)
+
+			var actual []string
+			for _, i := range issues {
+				actual = append(actual, i.String())
+			}
+			require.Equal(t, tt.issues, actual)
+		})
+	}
+}
+
+func TestLint_Fixtures(t *testing.T) {
+	issues, err := Lint(loadTestFile(t, "response_bugs.xml"))
+	require.NoError(t, err)
+	require.Empty(t, issues)
+
+	// Empty scalars are accepted by the decoder, but are not valid according to the specification
+	issues, err = Lint(loadTestFile(t, "response_struct_empty_values.xml"))
+	require.NoError(t, err)
+	require.Len(t, issues, 5)
+	require.Equal(t, Issue{Line: 16, Path: "params.0.EmptyInt", Message: "invalid <int> value ''"}, issues[0])
+}
+
+func TestLint_Errors(t *testing.T) {
+	_, err := Lint([]byte(`<methodResponse><params>`))
+	require.EqualError(t, err, "XML syntax error on line 1: unexpected EOF")
+
+	_, err = Lint([]byte(`<html></html>`))
+	require.EqualError(t, err, "unexpected root element <html>, expected <methodCall> or <methodResponse>")
+
+	_, err = Lint([]byte(``))
+	require.EqualError(t, err, "document has no root element")
+}
+
+func TestFormat(t *testing.T) {
+	out, err := Format(loadTestFile(t, "response_struct.xml"))
+	require.NoError(t, err)
+	require.Equal(t, string(loadTestFile(t, "response_struct_formatted.xml")), string(out))
+
+	// Formatting is idempotent
+	again, err := Format(out)
+	require.NoError(t, err)
+	require.Equal(t, string(out), string(again))
+}
+
+func TestFormat_Call(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="ISO-8859-1"?>
+<methodCall>   <methodName>echo</methodName>
+<!-- comment -->
+<params><param><value><string> spaced &amp; escaped </string></value></param>
+<param><value>  untyped  </value></param>
+<param><value><int> 5 </int></value></param>
+<param><value><nil/></value></param>
+<param><value><array><data></data></array></value></param>
+</params></methodCall>`
+
+	out, err := Format([]byte(doc))
+	require.NoError(t, err)
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<methodCall>
+  <methodName>echo</methodName>
+  <params>
+    <param>
+      <value><string> spaced &amp; escaped </string></value>
+    </param>
+    <param>
+      <value>  untyped  </value>
+    </param>
+    <param>
+      <value><int>5</int></value>
+    </param>
+    <param>
+      <value><nil/></value>
+    </param>
+    <param>
+      <value>
+        <array>
+          <data/>
+        </array>
+      </value>
+    </param>
+  </params>
+</methodCall>
+`, string(out))
+}
+
+func TestFormat_Namespaces(t *testing.T) {
+	doc := `<methodResponse xmlns:ex="http://ws.apache.org/xmlrpc/namespaces/extensions"><params><param><value><ex:i8> 42 </ex:i8></value></param>` +
+		`<param><value><ex:nil/></value></param></params></methodResponse>`
+
+	out, err := Format([]byte(doc))
+	require.NoError(t, err)
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<methodResponse xmlns:ex="http://ws.apache.org/xmlrpc/namespaces/extensions">
+  <params>
+    <param>
+      <value><ex:i8>42</ex:i8></value>
+    </param>
+    <param>
+      <value><ex:nil/></value>
+    </param>
+  </params>
+</methodResponse>
+`, string(out))
+
+	// The formatted document is still decoded with the extension types
+	var reply struct {
+		Value int64
+		Nil   *string
+	}
+	require.NoError(t, (&StdDecoder{dialect: DialectApache}).DecodeRaw(out, &reply))
+	require.Equal(t, int64(42), reply.Value)
+	require.Nil(t, reply.Nil)
+}
@@ -1,6 +1,10 @@
 package xmlrpc
 
-import "net/http"
+import (
+	"io"
+	"net/http"
+	"reflect"
+)
 
 // Option is a function that configures a Client by mutating it
 type Option func(client *Client)
@@ -36,3 +40,224 @@ func SkipUnknownFields(skip bool) Option {
 		}
 	}
 }
+
+// LenientParams option allows the decoder to tolerate a mismatch between the number of response params and the decoding target.
+// Extra params are ignored, while missing ones leave corresponding fields untouched.
+// This is only effective if using standard client, which in turn uses StdDecoder.
+func LenientParams(lenient bool) Option {
+	return func(client *Client) {
+		if v, ok := client.codec.decoder.(*StdDecoder); ok {
+			v.lenientParams = lenient
+		}
+	}
+}
+
+// SortMapKeys option makes the encoder write members of maps sorted by key, instead of in random order of map iteration.
+// This makes request bodies deterministic, e.g. for request signing, caching or golden tests.
+// To keep members in a specific order, use OrderedStruct instead of a map.
+// This is only effective if using standard client, which in turn uses StdEncoder.
+func SortMapKeys(sorted bool) Option {
+	return func(client *Client) {
+		if v, ok := client.codec.encoder.(*StdEncoder); ok {
+			v.sortMapKeys = sorted
+		}
+	}
+}
+
+// SkipMethodNameValidation option allows calling methods with names containing characters not allowed by the specification
+// (letters, digits and _ . : /), which some servers use (e.g. hyphens). Such names are escaped instead of rejected.
+// This is only effective if using standard client, which in turn uses StdEncoder.
+func SkipMethodNameValidation(skip bool) Option {
+	return func(client *Client) {
+		if v, ok := client.codec.encoder.(*StdEncoder); ok {
+			v.skipMethodNameValidation = skip
+		}
+	}
+}
+
+// InvalidChars option sets how characters XML 1.0 cannot represent (e.g. control characters) are handled when encoding
+// strings and member names: replaced with U+FFFD (the default), stripped, or rejected with an error.
+// This is only effective if using standard client, which in turn uses StdEncoder.
+func InvalidChars(policy InvalidCharPolicy) Option {
+	return func(client *Client) {
+		if v, ok := client.codec.encoder.(*StdEncoder); ok {
+			v.invalidChars = policy
+		}
+	}
+}
+
+// XMLDeclaration option writes an XML declaration (<?xml version="1.0"?>) before requests, which some servers require.
+// This is only effective if using standard client, which in turn uses StdEncoder.
+func XMLDeclaration(enabled bool) Option {
+	return func(client *Client) {
+		if v, ok := client.codec.encoder.(*StdEncoder); ok {
+			v.xmlDeclaration = enabled
+		}
+	}
+}
+
+// RequestCharset option sets the charset (e.g. "ISO-8859-1" or "windows-1252") requests are encoded in, for servers
+// not accepting UTF-8. Requests declare the charset in the XML declaration and the charset parameter of Content-Type.
+// Calls fail if the charset is not supported, or an argument has a character the charset cannot represent.
+// This is only effective if using standard client, which in turn uses StdEncoder.
+func RequestCharset(label string) Option {
+	return func(client *Client) {
+		if v, ok := client.codec.encoder.(*StdEncoder); ok {
+			v.charset = label
+		}
+	}
+}
+
+// Dialect option configures encoding and decoding for a family of servers bending the XML-RPC specification,
+// e.g. DialectPython, DialectApache or DialectPHP: integer and time formats, nil values, extensions and quirks of responses.
+// This is only effective if using standard client, which in turn uses StdEncoder and StdDecoder.
+func Dialect(profile DialectProfile) Option {
+	return func(client *Client) {
+		if v, ok := client.codec.encoder.(*StdEncoder); ok {
+			v.dialect = profile
+		}
+		if v, ok := client.codec.decoder.(*StdDecoder); ok {
+			v.dialect = profile
+		}
+	}
+}
+
+// RepairResponses option enables repair mode of the decoder, recovering malformed responses of broken servers, which are
+// otherwise rejected: bare '&' characters are escaped, characters XML 1.0 cannot represent are dropped, and responses
+// are decoded using FallbackCharset when their declared encoding is unknown or wrong. Every repair is reported to report,
+// which may be nil.
+// With StreamResponses option, responses are read into memory before they are decoded in repair mode.
+// This is only effective if using standard client, which in turn uses StdDecoder.
+func RepairResponses(report func(Repair)) Option {
+	return func(client *Client) {
+		if report == nil {
+			report = func(Repair) {}
+		}
+		if v, ok := client.codec.decoder.(*StdDecoder); ok {
+			v.repair = report
+		}
+	}
+}
+
+// FallbackCharset option sets the charset (e.g. "windows-1252") responses are decoded with in repair mode,
+// when their declared encoding is unknown, or does not match the body: it is not valid UTF-8 as declared (or implied,
+// without a declaration), or decoding it as declared produces control or replacement characters the fallback charset does not.
+// This is only effective if using standard client, which in turn uses StdDecoder, along with RepairResponses option.
+func FallbackCharset(label string) Option {
+	return func(client *Client) {
+		if v, ok := client.codec.decoder.(*StdDecoder); ok {
+			v.fallbackCharset = label
+		}
+	}
+}
+
+// UploadProgress option sets a callback reporting progress of <base64> data streamed from io.Reader arguments.
+// This is only effective if using standard client, which in turn uses StdEncoder.
+func UploadProgress(progress ProgressFunc) Option {
+	return func(client *Client) {
+		if v, ok := client.codec.encoder.(*StdEncoder); ok {
+			v.progress = progress
+		}
+	}
+}
+
+// Base64Sink option registers a sink of decoded <base64> data for reply fields of type T, which need not be writers.
+// For each such field receiving <base64> data, sink is called with a pointer to the field, and the data is written
+// to the writer it returns, instead of being assigned. Data is assigned as usual when sink returns nil.
+// For example, a sink for *os.File fields may create the file to download data into:
+//
+//	xmlrpc.Base64Sink(func(f **os.File) io.Writer {
+//		*f, _ = os.CreateTemp("", "artifact-*")
+//		return *f
+//	})
+//
+// This is only effective if using standard client, which in turn uses StdDecoder.
+func Base64Sink[T any](sink func(field *T) io.Writer) Option {
+	return func(client *Client) {
+		if v, ok := client.codec.decoder.(*StdDecoder); ok {
+			if v.sinks == nil {
+				v.sinks = make(map[reflect.Type]func(field reflect.Value) io.Writer)
+			}
+			v.sinks[reflect.TypeOf((*T)(nil)).Elem()] = func(field reflect.Value) io.Writer {
+				return sink(field.Interface().(*T))
+			}
+		}
+	}
+}
+
+// DownloadProgress option sets a callback reporting progress of <base64> data decoded into io.Writer reply fields.
+// This is only effective if using standard client, which in turn uses StdDecoder.
+func DownloadProgress(progress ProgressFunc) Option {
+	return func(client *Client) {
+		if v, ok := client.codec.decoder.(*StdDecoder); ok {
+			v.progress = progress
+		}
+	}
+}
+
+// StreamRequests option makes the client encode requests while sending them, with chunked transfer encoding,
+// instead of encoding the whole request into memory first to send it with Content-Length.
+// This lowers memory use with large arguments, but some servers require Content-Length, which is only sent by default.
+func StreamRequests(stream bool) Option {
+	return func(client *Client) {
+		client.codec.streamRequests = stream
+	}
+}
+
+// StreamResponses option makes the client decode responses directly from the response body, as StdDecoder.DecodeStream does,
+// instead of reading the whole body into memory and unmarshalling it into a Response first.
+// This considerably lowers memory use with large responses. Limits of response parsing are enforced while decoding.
+// This is only effective if using standard client, which in turn uses StdDecoder.
+func StreamResponses(stream bool) Option {
+	return func(client *Client) {
+		client.codec.streaming = stream
+	}
+}
+
+// MaxResponseSize option limits size of response body in bytes. Larger responses fail with *LimitError.
+// Responses are not limited by default.
+func MaxResponseSize(size int64) Option {
+	return func(client *Client) {
+		client.codec.limits.responseSize = size
+	}
+}
+
+// MaxDepth option limits nesting of arrays and structs in a response (e.g. an array of structs has depth 2).
+// Deeper responses fail with *LimitError. Nesting is not limited by default.
+func MaxDepth(depth int) Option {
+	return func(client *Client) {
+		client.codec.limits.depth = depth
+	}
+}
+
+// MaxArrayLength option limits number of elements of each array in a response. Longer arrays fail with *LimitError.
+// Arrays are not limited by default.
+func MaxArrayLength(length int) Option {
+	return func(client *Client) {
+		client.codec.limits.arrayLength = length
+	}
+}
+
+// MaxStructMembers option limits number of members of each struct in a response. Larger structs fail with *LimitError.
+// Structs are not limited by default.
+func MaxStructMembers(members int) Option {
+	return func(client *Client) {
+		client.codec.limits.structMembers = members
+	}
+}
+
+// MaxStringSize option limits size in bytes of each <string> (or untyped) value in a response, as encoded in XML.
+// Larger strings fail with *LimitError. Strings are not limited by default.
+func MaxStringSize(size int) Option {
+	return func(client *Client) {
+		client.codec.limits.stringSize = size
+	}
+}
+
+// MaxBase64Size option limits size in bytes of each <base64> value in a response, before decoding.
+// Larger values fail with *LimitError. Base64 values are not limited by default.
+func MaxBase64Size(size int) Option {
+	return func(client *Client) {
+		client.codec.limits.base64Size = size
+	}
+}
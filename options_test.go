@@ -2,10 +2,13 @@ package xmlrpc
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -254,3 +257,187 @@ func TestClient_Option_SkipUnknownFields(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_Option_LenientParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		expect bool
+	}{
+		{
+			name:   "default setting",
+			expect: false,
+		},
+		{
+			name: "new setting - false",
+			opts: []Option{
+				LenientParams(false),
+			},
+			expect: false,
+		},
+		{
+			name: "new setting - true",
+			opts: []Option{
+				LenientParams(true),
+			},
+			expect: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverCalled := false
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				serverCalled = true
+				_, _ = fmt.Fprintln(w, string(loadTestFile(t, "response_simple.xml")))
+			}))
+			defer ts.Close()
+
+			c, err := NewClient(ts.URL, tt.opts...)
+			require.NoError(t, err)
+
+			var area string
+			err = c.Call("test.Method", nil, &area)
+			if tt.expect {
+				require.NoError(t, err)
+				require.Equal(t, "South Dakota", area)
+			} else {
+				require.Error(t, err)
+			}
+
+			require.True(t, serverCalled, "server must be called")
+		})
+	}
+}
+
+func TestClient_Option_SortMapKeys(t *testing.T) {
+	var body []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		_, _ = fmt.Fprintln(w, string(loadTestFile(t, "response_simple.xml")))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, SortMapKeys(true), LenientParams(true))
+	require.NoError(t, err)
+
+	args := map[string]any{"d": 4, "b": 2, "a": 1, "c": map[string]int{"z": 1, "y": 2}}
+	var area string
+	for i := 0; i < 5; i++ {
+		require.NoError(t, c.Call("test.Method", args, &area))
+		require.Equal(t, "<methodCall><methodName>test.Method</methodName><params><param><value><struct>"+
+			"<member><name>a</name><value><int>1</int></value></member>"+
+			"<member><name>b</name><value><int>2</int></value></member>"+
+			"<member><name>c</name><value><struct><member><name>y</name><value><int>2</int></value></member><member><name>z</name><value><int>1</int></value></member></struct></value></member>"+
+			"<member><name>d</name><value><int>4</int></value></member>"+
+			"</struct></value></param></params></methodCall>", string(body))
+	}
+}
+
+func TestClient_Option_SkipMethodNameValidation(t *testing.T) {
+	var body []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		_, _ = fmt.Fprintln(w, string(loadTestFile(t, "response_simple.xml")))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, LenientParams(true))
+	require.NoError(t, err)
+	var area string
+	require.ErrorContains(t, c.Call("d-name", nil, &area), "invalid method name 'd-name'")
+
+	c, err = NewClient(ts.URL, SkipMethodNameValidation(true), LenientParams(true))
+	require.NoError(t, err)
+	require.NoError(t, c.Call("d-name", nil, &area))
+	require.Equal(t, "<methodCall><methodName>d-name</methodName></methodCall>", string(body))
+}
+
+func TestClient_Option_StreamResponses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		file := "response_bugs.xml"
+		if strings.Contains(string(body), "fail") {
+			file = "response_fault.xml"
+		}
+		_, _ = w.Write(loadTestFile(t, file))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, StreamResponses(true), SkipUnknownFields(true))
+	require.NoError(t, err)
+	defer c.Close()
+
+	type Bug struct {
+		Id      int
+		Summary string
+	}
+	type result struct {
+		Bugs   []Bug
+		Faults []any
+	}
+	expected := result{Bugs: []Bug{{Id: 35, Summary: "Crash on startup"}, {Id: 36, Summary: "Typo in docs"}}}
+
+	for i := 0; i < 3; i++ {
+		var bugs result
+		require.NoError(t, c.Call("bugs.get", nil, &bugs))
+		require.Equal(t, expected, bugs)
+
+		bugs = result{}
+		require.NoError(t, c.CallContext(context.Background(), "bugs.get", nil, &bugs))
+		require.Equal(t, expected, bugs)
+
+		err = c.CallContext(context.Background(), "bugs.fail", nil, &bugs)
+		fault := &Fault{}
+		require.True(t, errors.As(err, &fault))
+		require.Equal(t, 4, fault.Code)
+
+		// Replies are not required
+		require.NoError(t, c.Call("bugs.get", nil, nil))
+	}
+}
+
+func TestClient_Option_StreamRequests(t *testing.T) {
+	tests := map[string]struct {
+		stream           bool
+		expectChunked    bool
+		expectLengthSent bool
+	}{
+		"buffered": {stream: false, expectLengthSent: true},
+		"streamed": {stream: true, expectChunked: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var body []byte
+			var chunked bool
+			var contentLength int64
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = io.ReadAll(r.Body)
+				chunked = len(r.TransferEncoding) == 1 && r.TransferEncoding[0] == "chunked"
+				contentLength = r.ContentLength
+				_, _ = w.Write(loadTestFile(t, "response_simple.xml"))
+			}))
+			defer ts.Close()
+
+			c, err := NewClient(ts.URL, StreamRequests(tt.stream), LenientParams(true))
+			require.NoError(t, err)
+			defer c.Close()
+
+			data := strings.Repeat("a", 100000)
+			var area string
+			require.NoError(t, c.CallArgs("test.Method", &area, data))
+			require.Equal(t, "South Dakota", area)
+
+			require.Equal(t, "<methodCall><methodName>test.Method</methodName><params><param><value><string>"+data+"</string></value></param></params></methodCall>", string(body))
+			require.Equal(t, tt.expectChunked, chunked)
+			require.Equal(t, tt.expectLengthSent, contentLength == int64(len(body)))
+
+			// Encoding errors are returned as they are, and the client keeps working
+			err = c.CallArgs("test.Method", &area, make(chan int))
+			require.ErrorContains(t, err, "cannot encoded provided method arguments")
+			require.NotContains(t, err.Error(), "Post")
+			require.NoError(t, c.CallArgs("test.Method", &area, data))
+		})
+	}
+}
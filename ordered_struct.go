@@ -0,0 +1,109 @@
+package xmlrpc
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+var orderedStructType = reflect.TypeOf(OrderedStruct{})
+
+// OrderedStruct is a <struct> value with members in a defined order, unlike maps whose members are encoded in random order.
+// Members are encoded in order of the slice, which is useful when a server (or a request signature) depends on member order.
+//
+// When used as a decoding target, members are filled in the order they appear in the response. Member values are decoded
+// the same way as into `any`, except for nested structs, which are decoded as OrderedStruct as well.
+type OrderedStruct []Member
+
+// Member is a single named member of an OrderedStruct.
+type Member struct {
+	Name  string
+	Value any
+}
+
+// Get returns value of the first member with the given name.
+func (s OrderedStruct) Get(name string) (any, bool) {
+	for _, m := range s {
+		if m.Name == name {
+			return m.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+func (e *StdEncoder) encodeOrderedStruct(w io.Writer, s OrderedStruct) error {
+	_, _ = io.WriteString(w, "<struct>")
+	for _, m := range s {
+		_, _ = io.WriteString(w, "<member>")
+		if err := e.encodeMemberName(w, m.Name); err != nil {
+			return fmt.Errorf("cannot encode struct member '%s': %w", m.Name, err)
+		}
+		if err := e.encodeValue(w, m.Value); err != nil {
+			return fmt.Errorf("cannot encode value of struct member '%s': %w", m.Name, err)
+		}
+		_, _ = io.WriteString(w, "</member>")
+	}
+	_, _ = io.WriteString(w, "</struct>")
+
+	return nil
+}
+
+// decodeOrderedStruct decodes a <struct> into an OrderedStruct.
+func (d *StdDecoder) decodeOrderedStruct(value *ResponseValue) (OrderedStruct, error) {
+	if len(value.Struct) == 0 {
+		if isEmptyStruct(value) {
+			return OrderedStruct{}, nil
+		}
+		return nil, fmt.Errorf(errFormatInvalidFieldType, reflect.Struct.String(), describeResponseValue(value))
+	}
+
+	s := make(OrderedStruct, len(value.Struct))
+	for i, m := range value.Struct {
+		v, err := d.decodeOrderedMember(&m.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed decoding struct member '%s': %w", m.Name, err)
+		}
+		s[i] = Member{Name: m.Name, Value: v}
+	}
+
+	return s, nil
+}
+
+// decodeOrderedMember decodes a member value of an OrderedStruct, keeping order of nested structs (also within arrays).
+func (d *StdDecoder) decodeOrderedMember(value *ResponseValue) (any, error) {
+	switch {
+	case len(value.Struct) != 0 || isEmptyStruct(value):
+		return d.decodeOrderedStruct(value)
+
+	case value.Array != nil:
+		items := make([]any, len(value.Array.Values))
+		for i, item := range value.Array.Values {
+			v, err := d.decodeOrderedMember(item)
+			if err != nil {
+				return nil, fmt.Errorf("failed decoding array item at index %d: %w", i, err)
+			}
+			items[i] = v
+		}
+		return items, nil
+
+	default:
+		var v any
+		if err := d.decodeValue(value, reflect.ValueOf(&v).Elem()); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+// describeResponseValue returns the XML-RPC type name of a response value, for error messages.
+func describeResponseValue(value *ResponseValue) string {
+	return newValue(value).Kind().String()
+}
+
+// isEmptyStruct reports whether the value is a <struct> without members, which has no members to be recognized by,
+// but its raw contents.
+func isEmptyStruct(value *ResponseValue) bool {
+	return len(value.Struct) == 0 && strings.HasPrefix(strings.TrimSpace(value.RawXML), "<struct")
+}
@@ -0,0 +1,102 @@
+package xmlrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedStruct_Encode(t *testing.T) {
+	s := OrderedStruct{
+		{Name: "z", Value: 1},
+		{Name: "a", Value: "text"},
+		{Name: "nested", Value: OrderedStruct{{Name: "y", Value: true}, {Name: "x", Value: nil}}},
+		{Name: "list", Value: []OrderedStruct{{{Name: "b", Value: 1.5}}}},
+	}
+	const expected = "<struct>" +
+		"<member><name>z</name><value><int>1</int></value></member>" +
+		"<member><name>a</name><value><string>text</string></value></member>" +
+		"<member><name>nested</name><value><struct><member><name>y</name><value><boolean>1</boolean></value></member><member><name>x</name><value><nil/></value></member></struct></value></member>" +
+		"<member><name>list</name><value><array><data><value><struct><member><name>b</name><value><double>1.500000</double></value></member></struct></value></data></array></value></member>" +
+		"</struct>"
+
+	tests := []struct {
+		name   string
+		args   any
+		expect string
+	}{
+		{name: "bare argument", args: s, expect: "<params><param><value>" + expected + "</value></param></params>"},
+		{name: "pointer argument", args: &s, expect: "<params><param><value>" + expected + "</value></param></params>"},
+		{name: "struct field", args: struct{ S OrderedStruct }{S: s}, expect: "<params><param><value>" + expected + "</value></param></params>"},
+		{name: "positional argument", args: Args{"id", s}, expect: "<params><param><value><string>id</string></value></param><param><value>" + expected + "</value></param></params>"},
+		{name: "empty", args: OrderedStruct{}, expect: "<params><param><value><struct></struct></value></param></params>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(strings.Builder)
+			require.NoError(t, (&StdEncoder{}).Encode(buf, "m", tt.args))
+			require.Equal(t, "<methodCall><methodName>m</methodName>"+tt.expect+"</methodCall>", buf.String())
+		})
+	}
+
+	err := (&StdEncoder{}).Encode(new(strings.Builder), "m", OrderedStruct{{Name: "c", Value: make(chan int)}})
+	require.ErrorContains(t, err, "cannot encode value of struct member 'c': unsupported type chan")
+}
+
+func TestOrderedStruct_Decode(t *testing.T) {
+	var s OrderedStruct
+	require.NoError(t, (&StdDecoder{}).DecodeRaw(loadTestFile(t, "response_struct.xml"), &s))
+
+	require.Equal(t, OrderedStruct{
+		{Name: "foo", Value: "bar"},
+		{Name: "baz", Value: 2},
+		{Name: "woBleBobble", Value: true},
+		{Name: "WoBleBobble2", Value: 34},
+		{Name: "2", Value: 3},
+		{Name: "array", Value: []any{200, "Some String", []any{"Nested String", 10, true}}},
+	}, s)
+
+	v, ok := s.Get("WoBleBobble2")
+	require.True(t, ok)
+	require.Equal(t, 34, v)
+
+	_, ok = s.Get("missing")
+	require.False(t, ok)
+}
+
+func TestOrderedStruct_Decode_Nested(t *testing.T) {
+	body := []byte(`<methodResponse><params><param><value><struct>
+		<member><name>b</name><value><struct><member><name>z</name><value><i4>1</i4></value></member><member><name>y</name><value><nil/></value></member></struct></value></member>
+		<member><name>a</name><value><array><data><value><struct><member><name>d</name><value>x</value></member><member><name>c</name><value><struct></struct></value></member></struct></value></data></array></value></member>
+		<member><name>e</name><value><struct/></value></member>
+	</struct></value></param></params></methodResponse>`)
+
+	reply := &struct {
+		S OrderedStruct
+	}{}
+	require.NoError(t, (&StdDecoder{}).DecodeRaw(body, reply))
+	require.Equal(t, OrderedStruct{
+		{Name: "b", Value: OrderedStruct{{Name: "z", Value: 1}, {Name: "y", Value: nil}}},
+		{Name: "a", Value: []any{OrderedStruct{{Name: "d", Value: "x"}, {Name: "c", Value: OrderedStruct{}}}}},
+		{Name: "e", Value: OrderedStruct{}},
+	}, reply.S)
+
+	// Round trip keeps the order
+	buf := new(strings.Builder)
+	require.NoError(t, (&StdEncoder{}).encodeResponse(buf, reply.S, true))
+	equal, err := Equal(body, []byte(buf.String()))
+	require.NoError(t, err)
+	require.True(t, equal)
+	require.Contains(t, buf.String(), "<member><name>b</name><value><struct><member><name>z</name>")
+}
+
+func TestOrderedStruct_Decode_Errors(t *testing.T) {
+	var s OrderedStruct
+	err := (&StdDecoder{}).DecodeRaw(loadTestFile(t, "response_array.xml"), &s)
+	require.EqualError(t, err, "invalid field type: expected 'struct', got 'array'")
+
+	err = (&StdDecoder{}).DecodeRaw([]byte(`<methodResponse><params><param><value><struct><member><name>a</name><value><int>x</int></value></member></struct></value></param></params></methodResponse>`), &s)
+	require.ErrorContains(t, err, "failed decoding struct member 'a'")
+}
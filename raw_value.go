@@ -0,0 +1,46 @@
+package xmlrpc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+)
+
+var rawValueType = reflect.TypeOf(RawValue{})
+
+// RawValue holds the untouched XML contents of a <value> element (without the <value> element itself).
+// It can be used to delay decoding of a part of the response, for example when its shape depends on a sibling member.
+//
+// When used as an argument, RawValue is written to the request verbatim - it must contain valid XML-RPC value contents.
+type RawValue []byte
+
+// Decode decodes the raw contents into provided pointer, following the same rules as StdDecoder does for response params.
+// Decoding uses default options of StdDecoder, use DecodeWith to decode with options of a decoder (e.g. its dialect).
+func (r RawValue) Decode(v any) error {
+	return r.DecodeWith(&StdDecoder{}, v)
+}
+
+// DecodeWith decodes the raw contents into provided pointer as Decode does, using options of the decoder.
+func (r RawValue) DecodeWith(d *StdDecoder, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer, got %T", v)
+	}
+
+	value := &ResponseValue{}
+	if err := xml.Unmarshal(r.element(), value); err != nil {
+		return fmt.Errorf("cannot parse raw value: %w", err)
+	}
+
+	return d.decodeValue(value, rv)
+}
+
+// element returns raw contents wrapped into a <value> element.
+func (r RawValue) element() []byte {
+	b := make([]byte, 0, len(r)+len("<value></value>"))
+	b = append(b, "<value>"...)
+	b = append(b, r...)
+	b = append(b, "</value>"...)
+
+	return b
+}
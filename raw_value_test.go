@@ -0,0 +1,78 @@
+package xmlrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawValue_Decode(t *testing.T) {
+	type Comment struct {
+		Author string
+		Text   string
+	}
+
+	type Event struct {
+		Type string
+		Data RawValue
+	}
+
+	v := &struct {
+		Events []Event
+	}{}
+
+	dec := &StdDecoder{}
+	require.NoError(t, dec.DecodeRaw(loadTestFile(t, "response_polymorphic.xml"), v))
+	require.Len(t, v.Events, 2)
+
+	require.Equal(t, "comment", v.Events[0].Type)
+	comment := &Comment{}
+	require.NoError(t, v.Events[0].Data.Decode(comment))
+	require.Equal(t, &Comment{Author: "jane", Text: "Looks good & works"}, comment)
+
+	require.Equal(t, "status", v.Events[1].Type)
+	var transition []string
+	require.NoError(t, v.Events[1].Data.Decode(&transition))
+	require.Equal(t, []string{"NEW", "RESOLVED"}, transition)
+
+	// Raw contents are kept untouched
+	require.Equal(t, `<array><data><value><string>NEW</string></value><value><string>RESOLVED</string></value></data></array>`, string(v.Events[1].Data))
+}
+
+func TestRawValue_Decode_Errors(t *testing.T) {
+	raw := RawValue(`<string>abc</string>`)
+
+	var s string
+	require.Error(t, raw.Decode(s))
+	require.Error(t, RawValue(`<string>abc</int>`).Decode(&s))
+
+	var i int
+	require.Error(t, raw.Decode(&i))
+}
+
+func TestRawValue_DecodeWith(t *testing.T) {
+	raw := RawValue(`Fish &amp; Chips`)
+
+	var s string
+	require.NoError(t, raw.Decode(&s))
+	require.Equal(t, "Fish &amp; Chips", s)
+
+	require.NoError(t, raw.DecodeWith(&StdDecoder{dialect: DialectPHP}, &s))
+	require.Equal(t, "Fish & Chips", s)
+}
+
+func TestRawValue_Encode(t *testing.T) {
+	buf := new(strings.Builder)
+	enc := &StdEncoder{}
+	err := enc.Encode(buf, "myMethod", &struct {
+		Raw RawValue
+		Int int
+	}{
+		Raw: RawValue(`<struct><member><name>a</name><value><i4>1</i4></value></member></struct>`),
+		Int: 2,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, `<methodCall><methodName>myMethod</methodName><params><param><value><struct><member><name>a</name><value><i4>1</i4></value></member></struct></value></param><param><value><int>2</int></value></param></params></methodCall>`, buf.String())
+}
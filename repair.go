@@ -0,0 +1,160 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+)
+
+// Repair is a fix of a malformed response, made by the decoder in repair mode (see RepairResponses).
+type Repair struct {
+	// Offset is the position of the repaired text in the response, decoded into UTF-8.
+	Offset int
+	// Description of the repair, e.g. `escaped bare '&'`.
+	Description string
+}
+
+func (r Repair) String() string {
+	return fmt.Sprintf("offset %d: %s", r.Offset, r.Description)
+}
+
+var (
+	// xmlDeclaration matches the XML declaration, capturing its encoding attribute with the leading space (if any) and the label.
+	xmlDeclaration = regexp.MustCompile(`^\s*<\?xml[^>]*?(\s+encoding\s*=\s*["']([^"']*)["'])[^>]*\?>`)
+	// xmlReference matches a reference to a predefined entity or a character, which is not a bare '&'.
+	xmlReference = regexp.MustCompile(`^&(amp|lt|gt|apos|quot|#[0-9]+|#x[0-9a-fA-F]+);`)
+)
+
+// repairBody fixes common defects of responses produced by broken servers, reporting every fix:
+// the body is decoded into UTF-8 (using the fallback charset when the declared one is unknown or wrong),
+// characters XML 1.0 cannot represent are dropped, and bare '&' characters are escaped.
+//
+// The returned body declares no encoding, as it is UTF-8.
+func (d *StdDecoder) repairBody(body []byte) []byte {
+	body = d.repairCharset(body)
+
+	out := make([]byte, 0, len(body))
+	for i := 0; i < len(body); {
+		// Sections without references are kept as they are
+		if section := skippedSection(body[i:]); section > 0 {
+			out = append(out, body[i:i+section]...)
+			i += section
+			continue
+		}
+
+		if body[i] == '&' {
+			if !xmlReference.Match(body[i:min(i+16, len(body))]) {
+				d.repair(Repair{Offset: i, Description: "escaped bare '&'"})
+				out = append(out, "&amp;"...)
+				i++
+				continue
+			}
+		}
+
+		r, size := utf8.DecodeRune(body[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			d.repair(Repair{Offset: i, Description: fmt.Sprintf("dropped invalid UTF-8 byte 0x%02X", body[i])})
+		case !isXMLChar(r):
+			d.repair(Repair{Offset: i, Description: fmt.Sprintf("dropped invalid character %U", r)})
+		default:
+			out = append(out, body[i:i+size]...)
+		}
+		i += size
+	}
+
+	return out
+}
+
+// skippedSection returns length of a CDATA section, comment or processing instruction at the start of b, or 0 if there is none.
+func skippedSection(b []byte) int {
+	for _, s := range [][2]string{{"<![CDATA[", "]]>"}, {"<!--", "-->"}, {"<?", "?>"}} {
+		if !bytes.HasPrefix(b, []byte(s[0])) {
+			continue
+		}
+		if end := bytes.Index(b[len(s[0]):], []byte(s[1])); end >= 0 {
+			return len(s[0]) + end + len(s[1])
+		}
+	}
+
+	return 0
+}
+
+// repairCharset decodes the body into UTF-8 from its declared encoding, or from the fallback charset when the declared
+// encoding is unknown, or does not match the body (see mismatchedCharset). Encoding is then removed from the XML declaration.
+func (d *StdDecoder) repairCharset(body []byte) []byte {
+	label := "utf-8"
+	m := xmlDeclaration.FindSubmatchIndex(body)
+	if m != nil {
+		label = strings.TrimSpace(string(body[m[4]:m[5]]))
+		// Remove the encoding, which no longer applies
+		body = append(append(make([]byte, 0, len(body)), body[:m[2]]...), body[m[3]:]...)
+	}
+
+	enc, name := charset.Lookup(label)
+	switch {
+	case enc == nil:
+		if d.fallbackCharset == "" {
+			d.repair(Repair{Description: fmt.Sprintf("ignored unknown encoding '%s'", label)})
+			return body
+		}
+		d.repair(Repair{Description: fmt.Sprintf("decoded as %s instead of unknown encoding '%s'", d.fallbackCharset, label)})
+
+	case name == "utf-8":
+		if utf8.Valid(body) || d.fallbackCharset == "" {
+			return body
+		}
+		d.repair(Repair{Description: fmt.Sprintf("decoded as %s instead of %s, which is not valid", d.fallbackCharset, label)})
+
+	default:
+		decoded, err := enc.NewDecoder().Bytes(body)
+		if err != nil {
+			return body
+		}
+		if fallback, fallbackName := charset.Lookup(d.fallbackCharset); fallback == nil || fallbackName == name ||
+			!mismatchedCharset(body, decoded, fallback, fallbackName) {
+			return decoded
+		}
+		d.repair(Repair{Description: fmt.Sprintf("decoded as %s instead of %s, which does not match the body", d.fallbackCharset, label)})
+	}
+
+	fallback, _ := charset.Lookup(d.fallbackCharset)
+	if fallback == nil {
+		d.repair(Repair{Description: fmt.Sprintf("ignored unknown fallback encoding '%s'", d.fallbackCharset)})
+		return body
+	}
+	if decoded, err := fallback.NewDecoder().Bytes(body); err == nil {
+		return decoded
+	}
+
+	return body
+}
+
+// mismatchedCharset reports whether the body, decoded from its declared charset, is rather encoded in the fallback charset.
+// Valid multi-byte UTF-8 is unlikely to appear by chance in text of other charsets, while decoding from a wrong charset
+// tends to produce C1 control characters (unused in text) or replacement characters.
+func mismatchedCharset(body, decoded []byte, fallback encoding.Encoding, fallbackName string) bool {
+	if fallbackName == "utf-8" {
+		return utf8.Valid(body) && bytes.IndexFunc(body, func(r rune) bool { return r >= utf8.RuneSelf }) != -1
+	}
+
+	alternative, err := fallback.NewDecoder().Bytes(body)
+	return err == nil && suspiciousChars(alternative) < suspiciousChars(decoded)
+}
+
+// suspiciousChars counts C1 control and replacement characters of decoded text.
+func suspiciousChars(text []byte) int {
+	count := 0
+	for _, r := range string(text) {
+		if r == utf8.RuneError || (r >= 0x80 && r <= 0x9f) {
+			count++
+		}
+	}
+
+	return count
+}
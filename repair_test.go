@@ -0,0 +1,169 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdDecoder_repairBody(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		fallback string
+		expect   string
+		repairs  []string
+	}{
+		"well-formed": {
+			body:   `<?xml version="1.0"?><value><string>a &amp; &lt;b&gt; &#233; &#xE9;</string></value>`,
+			expect: `<?xml version="1.0"?><value><string>a &amp; &lt;b&gt; &#233; &#xE9;</string></value>`,
+		},
+		"bare ampersands": {
+			body:    `<value><string>Tom & Jerry &co;&</string></value>`,
+			expect:  `<value><string>Tom &amp; Jerry &amp;co;&amp;</string></value>`,
+			repairs: []string{"offset 19: escaped bare '&'", "offset 27: escaped bare '&'", "offset 31: escaped bare '&'"},
+		},
+		"control characters": {
+			body:    "<value><string>a\x00b\x1bc\td</string></value>",
+			expect:  "<value><string>abc\td</string></value>",
+			repairs: []string{"offset 16: dropped invalid character U+0000", "offset 18: dropped invalid character U+001B"},
+		},
+		"sections kept": {
+			body:   "<!-- a & b --><value><![CDATA[a & b]]></value>",
+			expect: "<!-- a & b --><value><![CDATA[a & b]]></value>",
+		},
+		"invalid UTF-8": {
+			body:    "<value><string>caf\xe9</string></value>",
+			expect:  "<value><string>caf</string></value>",
+			repairs: []string{"offset 18: dropped invalid UTF-8 byte 0xE9"},
+		},
+		"invalid UTF-8 with fallback": {
+			body:     "<?xml version=\"1.0\" encoding=\"UTF-8\"?><value><string>caf\xe9 \x80</string></value>",
+			fallback: "windows-1252",
+			expect:   `<?xml version="1.0"?><value><string>café €</string></value>`,
+			repairs:  []string{"offset 0: decoded as windows-1252 instead of UTF-8, which is not valid"},
+		},
+		"declared charset": {
+			body:   "<?xml version='1.0' encoding='ISO-8859-1'?><value><string>caf\xe9</string></value>",
+			expect: `<?xml version='1.0'?><value><string>café</string></value>`,
+		},
+		"declared charset not matching UTF-8 body": {
+			body:     `<?xml version="1.0" encoding="ISO-8859-1"?><value><string>café</string></value>`,
+			fallback: "utf-8",
+			expect:   `<?xml version="1.0"?><value><string>café</string></value>`,
+			repairs:  []string{"offset 0: decoded as utf-8 instead of ISO-8859-1, which does not match the body"},
+		},
+		"declared charset producing control characters": {
+			body:     "<?xml version=\"1.0\" encoding=\"ISO-8859-2\"?><value><string>\x80 \x93x\x94</string></value>",
+			fallback: "windows-1252",
+			expect:   `<?xml version="1.0"?><value><string>€ “x”</string></value>`,
+			repairs:  []string{"offset 0: decoded as windows-1252 instead of ISO-8859-2, which does not match the body"},
+		},
+		"declared charset matching body": {
+			body:     "<?xml version=\"1.0\" encoding=\"ISO-8859-2\"?><value><string>\xb1</string></value>",
+			fallback: "windows-1252",
+			expect:   `<?xml version="1.0"?><value><string>ą</string></value>`,
+		},
+		"unknown charset": {
+			body:    `<?xml version="1.0" encoding="x-legacy"?><value><string>café</string></value>`,
+			expect:  `<?xml version="1.0"?><value><string>café</string></value>`,
+			repairs: []string{"offset 0: ignored unknown encoding 'x-legacy'"},
+		},
+		"unknown charset with fallback": {
+			body:     "<?xml version=\"1.0\" encoding=\"x-legacy\"?><value><string>caf\xe9</string></value>",
+			fallback: "latin1",
+			expect:   `<?xml version="1.0"?><value><string>café</string></value>`,
+			repairs:  []string{"offset 0: decoded as latin1 instead of unknown encoding 'x-legacy'"},
+		},
+		"unknown fallback": {
+			body:     "<value><string>caf\xe9</string></value>",
+			fallback: "x-legacy",
+			expect:   "<value><string>caf</string></value>",
+			repairs: []string{
+				"offset 0: decoded as x-legacy instead of utf-8, which is not valid",
+				"offset 0: ignored unknown fallback encoding 'x-legacy'",
+				"offset 18: dropped invalid UTF-8 byte 0xE9",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var repairs []string
+			dec := &StdDecoder{fallbackCharset: tt.fallback, repair: func(r Repair) {
+				repairs = append(repairs, r.String())
+			}}
+
+			require.Equal(t, tt.expect, string(dec.repairBody([]byte(tt.body))))
+			require.Equal(t, tt.repairs, repairs)
+		})
+	}
+}
+
+func TestStdDecoder_Repair(t *testing.T) {
+	body := []byte("<?xml version=\"1.0\" encoding=\"x-legacy\"?><methodResponse><params><param><value><struct>" +
+		"<member><name>title</name><value><string>Fish & Chips\x0c</string></value></member>" +
+		"<member><name>author</name><value><string>Jos\xe9</string></value></member>" +
+		"</struct></value></param></params></methodResponse>")
+
+	type book struct {
+		Title  string
+		Author string
+	}
+
+	// Malformed responses are rejected unless repaired
+	require.Error(t, (&StdDecoder{}).DecodeRaw(body, &book{}))
+
+	var repairs []Repair
+	dec := &StdDecoder{fallbackCharset: "windows-1252", repair: func(r Repair) {
+		repairs = append(repairs, r)
+	}}
+
+	v := &book{}
+	require.NoError(t, dec.DecodeRaw(body, v))
+	require.Equal(t, &book{Title: "Fish & Chips", Author: "José"}, v)
+	require.Len(t, repairs, 3)
+
+	repairs = nil
+	v = &book{}
+	require.NoError(t, dec.DecodeStream(bytes.NewReader(body), v))
+	require.Equal(t, &book{Title: "Fish & Chips", Author: "José"}, v)
+	require.Len(t, repairs, 3)
+}
+
+func TestClient_Option_RepairResponses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?><methodResponse><params><param>" +
+			"<value><string>R&D \xa9 2001</string></value></param></params></methodResponse>"))
+	}))
+	defer ts.Close()
+
+	for _, stream := range []bool{false, true} {
+		var repairs []Repair
+		c, err := NewClient(ts.URL, StreamResponses(stream), FallbackCharset("iso-8859-1"),
+			RepairResponses(func(r Repair) { repairs = append(repairs, r) }))
+		require.NoError(t, err)
+
+		var s string
+		require.NoError(t, c.CallArgs("legacy.get", &s))
+		require.Equal(t, "R&D © 2001", s)
+		require.Len(t, repairs, 2, "stream: %v", stream)
+
+		s = ""
+		require.NoError(t, c.CallContext(context.Background(), "legacy.get", nil, &s))
+		require.Equal(t, "R&D © 2001", s)
+		require.NoError(t, c.Close())
+	}
+
+	// Repairs need not be reported
+	c, err := NewClient(ts.URL, RepairResponses(nil))
+	require.NoError(t, err)
+	defer c.Close()
+
+	var s string
+	require.NoError(t, c.CallArgs("legacy.get", &s))
+	require.Equal(t, "R&D  2001", s)
+}
@@ -0,0 +1,263 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+
+	"golang.org/x/net/html/charset"
+)
+
+// Server dispatches XML-RPC method calls received over HTTP to registered Go functions.
+// It implements http.Handler, so it can be mounted on any path of an HTTP server:
+//
+//	s := xmlrpc.NewServer()
+//	_ = s.Register("sample.add", func(ctx context.Context, args struct{ A, B int }) (int, error) {
+//		return args.A + args.B, nil
+//	})
+//	http.Handle("/RPC2", s)
+type Server struct {
+	mutex   sync.RWMutex
+	methods map[string]*serverMethod
+
+	encoder *StdEncoder
+	decoder *StdDecoder
+	limits  limits
+}
+
+// DefaultMaxRequestSize is the size limit of request bodies in bytes used by Server, unless set with RequestLimits.
+const DefaultMaxRequestSize = 10 << 20
+
+// ServerOption is a function that modifies the behavior of Server.
+type ServerOption func(server *Server)
+
+// RequestLimits option applies resource limits to requests received by the server, using the same options as for
+// client responses: MaxResponseSize (limiting request body size, DefaultMaxRequestSize by default), MaxDepth, MaxArrayLength,
+// MaxStructMembers, MaxStringSize and MaxBase64Size. Other options are ignored.
+// Requests exceeding a limit are answered with a fault with FaultInvalidRequest code.
+func RequestLimits(opts ...Option) ServerOption {
+	return func(server *Server) {
+		client := &Client{codec: &Codec{limits: server.limits}}
+		for _, opt := range opts {
+			opt(client)
+		}
+		server.limits = client.codec.limits
+	}
+}
+
+type serverMethod struct {
+	fn         reflect.Value
+	hasContext bool
+	argType    reflect.Type
+	hasReply   bool
+	// minParams and maxParams are the number of params accepted by the argument, maxParams is -1 if it is not limited
+	minParams int
+	maxParams int
+}
+
+// methodCall is the parsed XML-RPC request body.
+type methodCall struct {
+	MethodName string           `xml:"methodName"`
+	Params     []*ResponseParam `xml:"params>param"`
+}
+
+// NewServer creates a Server without any registered methods.
+func NewServer(opts ...ServerOption) *Server {
+	s := &Server{
+		methods: make(map[string]*serverMethod),
+		encoder: &StdEncoder{},
+		// Missing trailing params leave the fields of optional params untouched
+		decoder: &StdDecoder{lenientParams: true},
+		limits:  limits{responseSize: DefaultMaxRequestSize},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Register makes fn available to be called as methodName.
+//
+// Signature of fn follows the same rules as func fields used with Client.Bind: an optional context.Context
+// (which is the context of the HTTP request), followed by at most one argument, and either an error, or a reply value and an error.
+// The argument receives request params the same way as reply of Client.Call receives response params:
+// a struct receives a param per exported field, while other types receive a single param.
+// Trailing params of pointer types, or tagged with `xmlrpc:",omitempty"`, are optional and may be omitted by the caller,
+// leaving corresponding fields untouched, while sending fewer or more params than accepted results in a fault.
+//
+// Returned *Fault errors are sent to the caller as is, while other errors result in a fault with FaultApplicationError code.
+func (s *Server) Register(methodName string, fn any) error {
+	fnValue := reflect.ValueOf(fn)
+	if fnValue.Kind() != reflect.Func || fnValue.IsNil() {
+		return fmt.Errorf("cannot register method '%s': expected a function, got %T", methodName, fn)
+	}
+
+	fnType := fnValue.Type()
+	if err := validateBindSignature(fnType); err != nil {
+		return fmt.Errorf("cannot register method '%s': %w", methodName, err)
+	}
+
+	m := &serverMethod{
+		fn:         fnValue,
+		hasContext: fnType.NumIn() > 0 && fnType.In(0) == contextType,
+		hasReply:   fnType.NumOut() == 2, //nolint:mnd // (reply, error)
+	}
+	if n := fnType.NumIn(); n > 0 && (!m.hasContext || n > 1) {
+		m.argType = fnType.In(n - 1)
+		m.minParams, m.maxParams = paramCounts(m.argType)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.methods[methodName]; ok {
+		return fmt.Errorf("cannot register method '%s': method already registered", methodName)
+	}
+	s.methods[methodName] = m
+
+	return nil
+}
+
+// Methods returns sorted names of registered methods.
+func (s *Server) Methods() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	names := make([]string, 0, len(s.methods))
+	for name := range s.methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ServeHTTP handles a single XML-RPC method call.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "XML-RPC requests must use POST method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body := new(bytes.Buffer)
+	if err := s.call(r.Context(), r.Body, body); err != nil {
+		body.Reset()
+		_ = s.encoder.encodeFault(body, toFault(err))
+	}
+
+	w.Header().Set("Content-Type", "text/xml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = body.WriteTo(w)
+}
+
+// call parses the method call from r, invokes the method and writes the response to w.
+func (s *Server) call(ctx context.Context, r io.Reader, w io.Writer) error {
+	body, err := s.limits.readBody(r)
+	if err == nil {
+		err = s.limits.check(body)
+	}
+	if limitErr := (&LimitError{}); errors.As(err, &limitErr) {
+		return &Fault{Code: FaultInvalidRequest, String: fmt.Sprintf("request exceeds %s limit of %d", limitErr.Limit, limitErr.Max)}
+	} else if err != nil {
+		return &Fault{Code: FaultParseError, String: fmt.Sprintf("cannot read method call: %v", err)}
+	}
+
+	call := &methodCall{}
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	dec.CharsetReader = charset.NewReaderLabel
+	if err := dec.Decode(call); err != nil {
+		return &Fault{Code: FaultParseError, String: fmt.Sprintf("cannot parse method call: %v", err)}
+	}
+
+	s.mutex.RLock()
+	m, ok := s.methods[call.MethodName]
+	s.mutex.RUnlock()
+	if !ok {
+		return &Fault{Code: FaultMethodNotFound, String: fmt.Sprintf("method '%s' not found", call.MethodName)}
+	}
+
+	var in []reflect.Value
+	if m.hasContext {
+		in = append(in, reflect.ValueOf(ctx))
+	}
+
+	if m.argType != nil {
+		arg := reflect.New(m.argType)
+
+		// A single <struct> or <array> param may be decoded into the params struct itself
+		whole := len(call.Params) == 1 && isParamsStruct(m.argType) && s.decoder.decodesWhole(&call.Params[0].Value, exportedFields(arg.Elem()))
+		switch {
+		case m.maxParams != -1 && len(call.Params) > m.maxParams:
+			return &Fault{Code: FaultInvalidParams, String: fmt.Sprintf("method '%s' accepts at most %d params, got %d", call.MethodName, m.maxParams, len(call.Params))}
+		case len(call.Params) < m.minParams && !whole:
+			return &Fault{Code: FaultInvalidParams, String: fmt.Sprintf("method '%s' requires at least %d params, got %d", call.MethodName, m.minParams, len(call.Params))}
+		}
+
+		if err := s.decoder.Decode(&Response{Params: call.Params}, arg.Interface()); err != nil {
+			return &Fault{Code: FaultInvalidParams, String: fmt.Sprintf("invalid params of method '%s': %v", call.MethodName, err)}
+		}
+		in = append(in, arg.Elem())
+	} else if len(call.Params) != 0 {
+		return &Fault{Code: FaultInvalidParams, String: fmt.Sprintf("method '%s' does not accept params, got %d", call.MethodName, len(call.Params))}
+	}
+
+	out := m.fn.Call(in)
+	if err, _ := out[len(out)-1].Interface().(error); err != nil {
+		return err
+	}
+
+	var reply any
+	if m.hasReply {
+		reply = out[0].Interface()
+	}
+
+	if err := s.encoder.encodeResponse(w, reply, m.hasReply); err != nil {
+		return &Fault{Code: FaultInternalError, String: err.Error()}
+	}
+
+	return nil
+}
+
+// paramCounts returns the minimum and maximum number of params accepted by an argument of type t, with -1 for no maximum.
+// Params of a struct are optional when they are trailing and either pointers or tagged with `xmlrpc:",omitempty"`,
+// while a single param is optional for pointers, and lists of params accept any number of them.
+func paramCounts(t reflect.Type) (int, int) {
+	switch {
+	case t == paramListAnyType || t == paramListValueType:
+		return 0, -1
+	case isParamsStruct(t):
+		exported := planOf(t).exported
+		minParams := len(exported)
+		for ; minParams > 0; minParams-- {
+			f := t.Field(exported[minParams-1])
+			if f.Type.Kind() != reflect.Ptr && !hasTagOption(f.Tag.Get("xmlrpc"), omitEmptyTagOption) {
+				break
+			}
+		}
+		return minParams, len(exported)
+	case t.Kind() == reflect.Ptr:
+		return 0, 1
+	default:
+		return 1, 1
+	}
+}
+
+// toFault converts an error returned by a method into a Fault.
+func toFault(err error) *Fault {
+	fault := &Fault{}
+	if errors.As(err, &fault) {
+		return fault
+	}
+
+	return &Fault{Code: FaultApplicationError, String: err.Error()}
+}
@@ -0,0 +1,285 @@
+package xmlrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	type Bug struct {
+		Id      int
+		Summary string
+		Created time.Time
+	}
+
+	s := NewServer()
+	require.NoError(t, s.Register("sample.add", func(args struct{ A, B int }) (int, error) {
+		return args.A + args.B, nil
+	}))
+	require.NoError(t, s.Register("Bug.get", func(ctx context.Context, id int) (Bug, error) {
+		require.NotNil(t, ctx)
+		if id != 35 {
+			return Bug{}, &Fault{Code: 101, String: "bug not found"}
+		}
+		return Bug{Id: 35, Summary: "Crash", Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}, nil
+	}))
+	require.NoError(t, s.Register("Bug.update", func(bug Bug) error {
+		return errors.New("read-only")
+	}))
+	require.NoError(t, s.Register("system.ping", func() error {
+		return nil
+	}))
+	require.NoError(t, s.Register("Bug.comment", func(args struct {
+		Id      int
+		Comment *string
+	}) (Value, error) {
+		if args.Comment == nil {
+			return Value{kind: KindNil}, nil
+		}
+		return Value{kind: KindStruct, members: []ValueMember{
+			{Name: "id", Value: Value{kind: KindInt, text: strconv.Itoa(args.Id)}},
+			{Name: "comment", Value: Value{kind: KindString, text: *args.Comment}},
+		}}, nil
+	}))
+	require.Equal(t, []string{"Bug.comment", "Bug.get", "Bug.update", "sample.add", "system.ping"}, s.Methods())
+
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL)
+	require.NoError(t, err)
+	defer c.Close()
+
+	ctx := context.Background()
+
+	sum, err := Invoke[int](ctx, c, "sample.add", Args{2, 3})
+	require.NoError(t, err)
+	require.Equal(t, 5, sum)
+
+	bug, err := Invoke[struct{ Bug Bug }](ctx, c, "Bug.get", Args{35})
+	require.NoError(t, err)
+	require.Equal(t, Bug{Id: 35, Summary: "Crash", Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}, bug.Bug)
+
+	require.NoError(t, c.CallContext(ctx, "system.ping", nil, nil))
+
+	// Trailing optional params may be left out, and Value replies keep their data types
+	type commentArgs struct {
+		Id      int
+		Comment *string `xmlrpc:",omitempty"`
+	}
+	comment, err := Invoke[Value](ctx, c, "Bug.comment", commentArgs{Id: 35, Comment: ptr("Fixed")})
+	require.NoError(t, err)
+	require.Equal(t, `{"id": 35, "comment": "Fixed"}`, comment.String())
+
+	comment, err = Invoke[Value](ctx, c, "Bug.comment", commentArgs{Id: 35})
+	require.NoError(t, err)
+	require.Equal(t, KindNil, comment.Kind())
+
+	tests := []struct {
+		name   string
+		method string
+		args   any
+		code   int
+		err    string
+	}{
+		{
+			name:   "returned fault",
+			method: "Bug.get",
+			args:   Args{36},
+			code:   101,
+			err:    "bug not found",
+		},
+		{
+			name:   "returned error",
+			method: "Bug.update",
+			args:   Args{Bug{Id: 35}},
+			code:   FaultApplicationError,
+			err:    "read-only",
+		},
+		{
+			name:   "unknown method",
+			method: "Bug.delete",
+			code:   FaultMethodNotFound,
+			err:    "method 'Bug.delete' not found",
+		},
+		{
+			name:   "invalid params",
+			method: "sample.add",
+			args:   Args{"two", 3},
+			code:   FaultInvalidParams,
+			err:    "invalid params of method 'sample.add'",
+		},
+		{
+			name:   "missing params",
+			method: "sample.add",
+			args:   Args{2},
+			code:   FaultInvalidParams,
+			err:    "method 'sample.add' requires at least 2 params, got 1",
+		},
+		{
+			name:   "missing single param",
+			method: "Bug.get",
+			code:   FaultInvalidParams,
+			err:    "method 'Bug.get' requires at least 1 params, got 0",
+		},
+		{
+			name:   "missing required param before optional one",
+			method: "Bug.comment",
+			code:   FaultInvalidParams,
+			err:    "method 'Bug.comment' requires at least 1 params, got 0",
+		},
+		{
+			name:   "too many params",
+			method: "sample.add",
+			args:   Args{1, 2, 3},
+			code:   FaultInvalidParams,
+			err:    "method 'sample.add' accepts at most 2 params, got 3",
+		},
+		{
+			name:   "too many params of single argument",
+			method: "Bug.get",
+			args:   Args{35, 36},
+			code:   FaultInvalidParams,
+			err:    "method 'Bug.get' accepts at most 1 params, got 2",
+		},
+		{
+			name:   "unexpected params",
+			method: "system.ping",
+			args:   Args{1},
+			code:   FaultInvalidParams,
+			err:    "method 'system.ping' does not accept params, got 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.CallContext(ctx, tt.method, tt.args, nil)
+
+			fT := &Fault{}
+			require.True(t, errors.As(err, &fT), "expected fault, got %v", err)
+			require.Equal(t, tt.code, fT.Code)
+			require.True(t, strings.HasPrefix(fT.String, tt.err), fT.String)
+		})
+	}
+}
+
+func TestServer_ParseError(t *testing.T) {
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL, "text/xml", strings.NewReader("<methodCall>")) //nolint:noctx // test request
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/xml", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	response, err := NewResponse(body)
+	require.NoError(t, err)
+
+	fault := (&StdDecoder{}).DecodeFault(response)
+	require.NotNil(t, fault)
+	require.Equal(t, FaultParseError, fault.Code)
+}
+
+func TestServer_RequestLimits(t *testing.T) {
+	s := NewServer(RequestLimits(MaxResponseSize(400), MaxDepth(2)))
+	require.NoError(t, s.Register("sample.echo", func(args []any) ([]any, error) {
+		return args, nil
+	}))
+	require.Equal(t, limits{responseSize: 400, depth: 2}, s.limits)
+	require.Equal(t, limits{responseSize: DefaultMaxRequestSize}, NewServer().limits)
+
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL)
+	require.NoError(t, err)
+	defer c.Close()
+
+	var reply []any
+	require.NoError(t, c.CallArgs("sample.echo", &reply, 1))
+	require.Equal(t, []any{[]any{1}}, reply)
+
+	tests := map[string]struct {
+		args Args
+		err  string
+	}{
+		"size": {
+			args: Args{strings.Repeat("a", 400)},
+			err:  "request exceeds response size limit of 400",
+		},
+		"depth": {
+			args: Args{[]any{[]any{[]any{1}}}},
+			err:  "request exceeds depth limit of 2",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := c.CallContext(context.Background(), "sample.echo", tt.args, &reply)
+
+			fT := &Fault{}
+			require.True(t, errors.As(err, &fT), "expected fault, got %v", err)
+			require.Equal(t, FaultInvalidRequest, fT.Code)
+			require.Equal(t, tt.err, fT.String)
+		})
+	}
+}
+
+func TestServer_MethodNotAllowed(t *testing.T) {
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL) //nolint:noctx // test request
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestServer_Register_Errors(t *testing.T) {
+	s := NewServer()
+	require.NoError(t, s.Register("ping", func() error { return nil }))
+
+	tests := []struct {
+		name string
+		fn   any
+		err  string
+	}{
+		{
+			name: "not a function",
+			fn:   42,
+			err:  "cannot register method 'm': expected a function, got int",
+		},
+		{
+			name: "too many arguments",
+			fn:   func(a, b int) error { return nil },
+			err:  "cannot register method 'm': function must accept at most one argument (besides context.Context), got 2",
+		},
+		{
+			name: "no error returned",
+			fn:   func() int { return 0 },
+			err:  "cannot register method 'm': single return value must be an error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.EqualError(t, s.Register("m", tt.fn), tt.err)
+		})
+	}
+
+	require.EqualError(t, s.Register("ping", func() error { return nil }), "cannot register method 'ping': method already registered")
+}
@@ -0,0 +1,256 @@
+package xmlrpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Endpoint schemes supported by Transport in addition to HTTP(S).
+const (
+	SchemeUnix     = "unix"
+	SchemeSCGI     = "scgi"
+	SchemeSCGIUnix = "scgi+unix"
+)
+
+// unixRequestPath is the HTTP path of requests sent over Unix domain sockets, as used by e.g. supervisord.
+const unixRequestPath = "/RPC2"
+
+// Transport is an http.RoundTripper that supports following endpoints in addition to HTTP(S) ones:
+//
+//   - unix:///path/to/socket - HTTP over a Unix domain socket, requests are sent to /RPC2 path;
+//   - scgi://host:port/path - SCGI over TCP (e.g. rTorrent);
+//   - scgi+unix:///path/to/socket - SCGI over a Unix domain socket.
+//
+// NewClient uses it automatically for these endpoints.
+type Transport struct {
+	// Base is used for HTTP(S) endpoints. If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+
+	mutex sync.Mutex
+	// HTTP transports by socket path
+	unix map[string]*http.Transport
+}
+
+// RoundTrip performs the request with the transport matching scheme of request URL.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.URL.Scheme {
+	case SchemeUnix:
+		return t.roundTripUnix(req)
+	case SchemeSCGI:
+		return roundTripSCGI(req, "tcp", req.URL.Host, req.URL.Path)
+	case SchemeSCGIUnix:
+		return roundTripSCGI(req, "unix", req.URL.Path, "/")
+	default:
+		return t.base().RoundTrip(req)
+	}
+}
+
+// CloseIdleConnections closes idle connections of underlying transports.
+func (t *Transport) CloseIdleConnections() {
+	type closeIdler interface {
+		CloseIdleConnections()
+	}
+
+	if base, ok := t.base().(closeIdler); ok {
+		base.CloseIdleConnections()
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, ut := range t.unix {
+		ut.CloseIdleConnections()
+	}
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+
+	return http.DefaultTransport
+}
+
+func (t *Transport) roundTripUnix(req *http.Request) (*http.Response, error) {
+	socket := req.URL.Path
+
+	t.mutex.Lock()
+	if t.unix == nil {
+		t.unix = make(map[string]*http.Transport)
+	}
+	ut, ok := t.unix[socket]
+	if !ok {
+		ut = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+			},
+		}
+		t.unix[socket] = ut
+	}
+	t.mutex.Unlock()
+
+	httpReq := req.Clone(req.Context())
+	httpReq.URL.Scheme = "http"
+	httpReq.URL.Host = "localhost"
+	httpReq.URL.Path = unixRequestPath
+	httpReq.Host = "localhost"
+
+	return ut.RoundTrip(httpReq)
+}
+
+// roundTripSCGI sends the request to SCGI server, and parses the CGI response.
+// See https://python.ca/scgi/protocol.txt for the protocol description.
+func roundTripSCGI(req *http.Request, network, address, path string) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if path == "" {
+		path = "/"
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(req.Context(), network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	// Abort reading of the response when the context is done
+	stop := context.AfterFunc(req.Context(), func() {
+		_ = conn.Close()
+	})
+
+	if _, err := conn.Write(scgiRequestHeader(req, path, len(body))); err != nil {
+		stop()
+		_ = conn.Close()
+		return nil, err
+	}
+	if _, err := conn.Write(body); err != nil {
+		stop()
+		_ = conn.Close()
+		return nil, err
+	}
+
+	resp, err := readSCGIResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		stop()
+		_ = conn.Close()
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+
+	resp.Body = &scgiBody{Reader: resp.Body, conn: conn, stop: stop}
+
+	return resp, nil
+}
+
+// scgiRequestHeader builds the netstring with request headers. CONTENT_LENGTH must be the first one.
+func scgiRequestHeader(req *http.Request, path string, contentLength int) []byte {
+	headers := new(bytes.Buffer)
+	writeHeader := func(name, value string) {
+		headers.WriteString(name)
+		headers.WriteByte(0)
+		headers.WriteString(value)
+		headers.WriteByte(0)
+	}
+
+	writeHeader("CONTENT_LENGTH", strconv.Itoa(contentLength))
+	writeHeader("SCGI", "1")
+	writeHeader("REQUEST_METHOD", req.Method)
+	writeHeader("REQUEST_URI", path)
+	writeHeader("SERVER_PROTOCOL", "HTTP/1.1")
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		switch value := req.Header.Get(name); name {
+		case "Content-Length":
+			continue
+		case "Content-Type":
+			writeHeader("CONTENT_TYPE", value)
+		default:
+			writeHeader("HTTP_"+strings.ToUpper(strings.ReplaceAll(name, "-", "_")), value)
+		}
+	}
+
+	return []byte(fmt.Sprintf("%d:%s,", headers.Len(), headers.Bytes()))
+}
+
+// readSCGIResponse parses CGI response, where status is passed in the "Status" header.
+// Responses starting with a HTTP status line are supported as well, as some servers send those.
+func readSCGIResponse(r *bufio.Reader, req *http.Request) (*http.Response, error) {
+	if prefix, err := r.Peek(len("HTTP/")); err == nil && string(prefix) == "HTTP/" {
+		return http.ReadResponse(r, req)
+	}
+
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read SCGI response header: %w", err)
+	}
+
+	resp := &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.0",
+		ProtoMajor: 1,
+		Header:     http.Header(header),
+		Body:       io.NopCloser(r),
+		Request:    req,
+	}
+
+	if status := header.Get("Status"); status != "" {
+		code, _, _ := strings.Cut(status, " ")
+		resp.StatusCode, err = strconv.Atoi(code)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SCGI response status '%s'", status)
+		}
+		resp.Status = status
+		resp.Header.Del("Status")
+	}
+
+	if cl := header.Get("Content-Length"); cl != "" {
+		resp.ContentLength, _ = strconv.ParseInt(cl, 10, 64)
+	} else {
+		resp.ContentLength = -1
+	}
+
+	return resp, nil
+}
+
+// scgiBody closes the connection once the response body is closed, as every SCGI request uses a new connection.
+type scgiBody struct {
+	io.Reader
+	conn net.Conn
+	stop func() bool
+}
+
+func (b *scgiBody) Close() error {
+	b.stop()
+	err := b.conn.Close()
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,177 @@
+package xmlrpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport_Unix(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "xmlrpc.sock")
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	s := testAddServer(t)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/RPC2", r.URL.Path)
+		s.ServeHTTP(w, r)
+	})}
+	go func() { _ = srv.Serve(l) }()
+	defer srv.Close()
+
+	c, err := NewClient("unix://" + socket)
+	require.NoError(t, err)
+	defer c.Close()
+
+	sum, err := Invoke[int](context.Background(), c, "sample.add", Args{2, 3})
+	require.NoError(t, err)
+	require.Equal(t, 5, sum)
+}
+
+func TestTransport_SCGI(t *testing.T) {
+	tests := []struct {
+		name     string
+		network  string
+		endpoint func(addr string) string
+		header   func(status int) string
+	}{
+		{
+			name:     "tcp",
+			network:  "tcp",
+			endpoint: func(addr string) string { return "scgi://" + addr + "/RPC2" },
+			header: func(status int) string {
+				return fmt.Sprintf("Status: %d %s\r\nContent-Type: text/xml\r\n\r\n", status, http.StatusText(status))
+			},
+		},
+		{
+			name:     "unix",
+			network:  "unix",
+			endpoint: func(addr string) string { return "scgi+unix://" + addr },
+			header: func(status int) string {
+				// Status is optional and defaults to 200
+				if status == http.StatusOK {
+					return "Content-Type: text/xml\r\n\r\n"
+				}
+				return fmt.Sprintf("Status: %d\r\n\r\n", status)
+			},
+		},
+		{
+			name:     "http status line",
+			network:  "tcp",
+			endpoint: func(addr string) string { return "scgi://" + addr },
+			header: func(status int) string {
+				return fmt.Sprintf("HTTP/1.1 %d %s\r\nContent-Type: text/xml\r\nConnection: close\r\n\r\n", status, http.StatusText(status))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := "127.0.0.1:0"
+			if tt.network == "unix" {
+				addr = filepath.Join(t.TempDir(), "scgi.sock")
+			}
+
+			l, err := net.Listen(tt.network, addr)
+			require.NoError(t, err)
+			defer l.Close()
+
+			go serveSCGI(t, l, testAddServer(t), tt.header)
+
+			c, err := NewClient(tt.endpoint(l.Addr().String()), UserAgent("scgi-test"))
+			require.NoError(t, err)
+			defer c.Close()
+
+			sum, err := Invoke[int](context.Background(), c, "sample.add", Args{2, 3})
+			require.NoError(t, err)
+			require.Equal(t, 5, sum)
+
+			_, err = Invoke[int](context.Background(), c, "sample.fail", nil)
+			require.EqualError(t, err, "bad response code: 500")
+		})
+	}
+}
+
+func Test_scgiRequestHeader(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "scgi://localhost:5000/RPC2", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "text/xml")
+	req.Header.Set("Content-Length", "12")
+	req.Header.Set("User-Agent", "test")
+
+	expected := "CONTENT_LENGTH\x0012\x00SCGI\x001\x00REQUEST_METHOD\x00POST\x00REQUEST_URI\x00/RPC2\x00SERVER_PROTOCOL\x00HTTP/1.1\x00" +
+		"CONTENT_TYPE\x00text/xml\x00HTTP_USER_AGENT\x00test\x00"
+	require.Equal(t, strconv.Itoa(len(expected))+":"+expected+",", string(scgiRequestHeader(req, "/RPC2", 12)))
+}
+
+func testAddServer(t *testing.T) *Server {
+	s := NewServer()
+	require.NoError(t, s.Register("sample.add", func(args struct{ A, B int }) (int, error) {
+		return args.A + args.B, nil
+	}))
+
+	return s
+}
+
+// serveSCGI is a minimal SCGI server, passing requests to the handler.
+// Method "sample.fail" results in status 500.
+func serveSCGI(t *testing.T, l net.Listener, h http.Handler, header func(status int) string) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		func() {
+			defer conn.Close()
+			r := bufio.NewReader(conn)
+
+			length, err := r.ReadString(':')
+			require.NoError(t, err)
+			n, err := strconv.Atoi(strings.TrimSuffix(length, ":"))
+			require.NoError(t, err)
+
+			raw := make([]byte, n+1) // including trailing comma
+			_, err = io.ReadFull(r, raw)
+			require.NoError(t, err)
+
+			fields := strings.Split(string(raw[:n]), "\x00")
+			headers := make(map[string]string)
+			for i := 0; i+1 < len(fields); i += 2 {
+				headers[fields[i]] = fields[i+1]
+			}
+			require.Equal(t, "CONTENT_LENGTH", fields[0])
+			require.Equal(t, "1", headers["SCGI"])
+			require.Equal(t, "text/xml", headers["CONTENT_TYPE"])
+			require.Equal(t, "scgi-test", headers["HTTP_USER_AGENT"])
+
+			contentLength, err := strconv.Atoi(headers["CONTENT_LENGTH"])
+			require.NoError(t, err)
+			body := make([]byte, contentLength)
+			_, err = io.ReadFull(r, body)
+			require.NoError(t, err)
+
+			status := http.StatusOK
+			if bytes.Contains(body, []byte("sample.fail")) {
+				status = http.StatusInternalServerError
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(headers["REQUEST_METHOD"], "/", bytes.NewReader(body)))
+
+			_, _ = fmt.Fprint(conn, header(status))
+			_, _ = rec.Body.WriteTo(conn)
+		}()
+	}
+}
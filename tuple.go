@@ -0,0 +1,52 @@
+package xmlrpc
+
+import (
+	"reflect"
+	"strings"
+)
+
+// tupleTagOption is the `xmlrpc` tag option that marks a struct type as a positional tuple.
+//
+// Tuples are encoded as (and decoded from) an <array> instead of a <struct>, with array elements
+// mapped to exported fields in the order they are defined on the type.
+// As Go does not allow tags on types, the marker is placed on a blank field:
+//
+//	type Torrent struct {
+//		_        struct{} `xmlrpc:",tuple"`
+//		Hash     string
+//		Name     string
+//		Complete bool
+//	}
+const tupleTagOption = "tuple"
+
+// isTuple reports whether provided type is a struct marked as a tuple.
+func isTuple(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Name == "_" && hasTagOption(f.Tag.Get("xmlrpc"), tupleTagOption) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasTagOption reports whether comma-separated options of the tag value (everything after the name) include provided option.
+func hasTagOption(tagValue, option string) bool {
+	index := strings.Index(tagValue, ",")
+	if index == -1 {
+		return false
+	}
+
+	for _, o := range strings.Split(tagValue[index+1:], ",") {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,114 @@
+package xmlrpc
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maxCachedMembers limits the number of member names cached per struct type. As many member names resolve to the same field
+// (e.g. "foo_bar" and "foo-bar"), a server could otherwise grow the cache without bounds.
+const maxCachedMembers = 1024
+
+// structPlans caches structPlan by struct type.
+var structPlans sync.Map // map[reflect.Type]*structPlan
+
+// structPlan holds what encoding and decoding of a struct type needs to know about its fields.
+// It is computed once per type, in the way encoding/json caches its encoders, as reflecting on fields and parsing their tags
+// for every value is costly.
+type structPlan struct {
+	// exported are indices of exported fields, in the order they are defined on the type
+	exported []int
+	// names are <member> names of exported fields, when the struct is encoded. Fields tagged with "-" have an empty name.
+	names []string
+	// tags are index sequences of fields by names from their `xmlrpc` tags, matching member names as they are
+	tags map[string][]int
+	// fields are index sequences of fields by the name member names are resolved to (see findFieldByNameOrTag)
+	fields map[string][]int
+
+	// members caches index sequences of fields by member name, as resolved by field
+	members       sync.Map
+	cachedMembers atomic.Int32
+}
+
+// planOf returns the plan of a struct type.
+func planOf(t reflect.Type) *structPlan {
+	if p, ok := structPlans.Load(t); ok {
+		return p.(*structPlan)
+	}
+
+	p, _ := structPlans.LoadOrStore(t, newStructPlan(t))
+	return p.(*structPlan)
+}
+
+func newStructPlan(t reflect.Type) *structPlan {
+	p := &structPlan{tags: make(map[string][]int), fields: make(map[string][]int)}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.IsExported() {
+			p.exported = append(p.exported, i)
+			p.names = append(p.names, memberName(f))
+		}
+
+		// Names from tags take precedence over names of fields, the first tagged field wins
+		name := getFieldNameFromTag(&f, "xmlrpc")
+		if p.fields[name] == nil {
+			p.fields[name] = []int{i}
+		}
+		if name != "" && p.tags[name] == nil {
+			p.tags[name] = []int{i}
+		}
+	}
+
+	// Fields are found by name as by reflect.Value.FieldByName, including fields promoted from embedded structs
+	for _, f := range reflect.VisibleFields(t) {
+		if p.fields[f.Name] == nil {
+			p.fields[f.Name] = f.Index
+		}
+	}
+
+	return p
+}
+
+// memberName returns the <member> name an exported field is encoded as, or an empty string if the field is tagged with "-".
+// Tags are parsed as when decoding, so options (e.g. ",omitempty") are not part of the name.
+func memberName(f reflect.StructField) string {
+	for _, tagName := range []string{"xml", "xmlrpc"} {
+		tagValue, ok := f.Tag.Lookup(tagName)
+		if !ok || tagValue == "" {
+			continue
+		}
+		if name := getFieldNameFromTag(&f, tagName); name != "" {
+			return name
+		}
+		if tagValue == "-" || strings.HasPrefix(tagValue, "-,") {
+			return ""
+		}
+	}
+
+	return f.Name
+}
+
+// field returns the field of struct v a <struct> member is decoded into, or an invalid value if there is none.
+func (p *structPlan) field(v reflect.Value, member string) reflect.Value {
+	if index, ok := p.members.Load(member); ok {
+		return v.FieldByIndex(index.([]int))
+	}
+
+	// Tags naming the member as it is take precedence over fields the member name is converted to
+	index := p.tags[member]
+	if index == nil {
+		index = p.fields[structMemberToFieldName(member)]
+	}
+	if index == nil {
+		return reflect.Value{}
+	}
+
+	if p.cachedMembers.Add(1) <= maxCachedMembers {
+		p.members.Store(member, index)
+	}
+
+	return v.FieldByIndex(index)
+}
@@ -0,0 +1,181 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_structPlan_field(t *testing.T) {
+	type Embedded struct {
+		Promoted string
+		Shadowed string
+	}
+	type target struct {
+		Embedded
+		Name     string `xmlrpc:"title"`
+		Title    string
+		Shadowed int
+		Tagged   int `xmlrpc:"otherName,omitempty"`
+		Ignored  int `xmlrpc:"-"`
+		private  int
+	}
+
+	v := reflect.ValueOf(&target{}).Elem()
+	plan := planOf(v.Type())
+
+	members := []string{"", "title", "Title", "name", "promoted", "shadowed", "otherName", "other_name", "tagged", "ignored", "private", "missing"}
+	for _, member := range members {
+		t.Run(member, func(t *testing.T) {
+			expected := findFieldByNameOrTag(v, structMemberToFieldName(member))
+			// Tags naming the member as it is take precedence
+			switch member {
+			case "title":
+				expected = v.FieldByName("Name")
+			case "otherName":
+				expected = v.FieldByName("Tagged")
+			}
+
+			// Twice, as the second lookup is served from cache
+			for i := 0; i < 2; i++ {
+				actual := plan.field(v, member)
+
+				require.Equal(t, expected.IsValid(), actual.IsValid())
+				if expected.IsValid() {
+					require.Equal(t, expected.Addr().Pointer(), actual.Addr().Pointer())
+				}
+			}
+		})
+	}
+
+	require.Equal(t, []int{0, 1, 2, 3, 4, 5}, plan.exported)
+	// Tag options are not part of names, and fields tagged with "-" are not encoded
+	require.Equal(t, []string{"Embedded", "title", "Title", "Shadowed", "otherName", ""}, plan.names)
+}
+
+func Test_structPlan_field_CacheLimit(t *testing.T) {
+	type target struct {
+		FooBar int
+	}
+
+	v := reflect.ValueOf(&target{}).Elem()
+	plan := newStructPlan(v.Type())
+	for i := 0; i < maxCachedMembers+10; i++ {
+		require.True(t, plan.field(v, "foo"+string(bytes.Repeat([]byte("_"), i+1))+"bar").IsValid())
+	}
+
+	cached := 0
+	plan.members.Range(func(_, _ any) bool {
+		cached++
+		return true
+	})
+	require.Equal(t, maxCachedMembers, cached)
+}
+
+type benchmarkRecord struct {
+	Id          int
+	Name        string
+	Description string
+	Price       float64
+	Enabled     bool
+	Owner       string
+	CreatedAt   string `xmlrpc:"CreatedAt"`
+	UpdatedAt   string
+}
+
+// clearStructPlans empties the cache, so that plans are computed again as without caching.
+func clearStructPlans() {
+	structPlans.Range(func(key, _ any) bool {
+		structPlans.Delete(key)
+		return true
+	})
+}
+
+// BenchmarkStdEncoder_EncodeStruct compares encoding with cached plans to computing them for every call.
+func BenchmarkStdEncoder_EncodeStruct(b *testing.B) {
+	records := make([]benchmarkRecord, 100)
+	for i := range records {
+		records[i] = benchmarkRecord{Id: i, Name: fmt.Sprintf("record %d", i), Price: 1.5, Enabled: true}
+	}
+
+	for _, cached := range []bool{false, true} {
+		b.Run(fmt.Sprintf("cached=%t", cached), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if !cached {
+					clearStructPlans()
+				}
+				if err := (&StdEncoder{}).Encode(io.Discard, "records.put", Args{records}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkStdDecoder_DecodeStruct compares decoding with cached plans to computing them for every call.
+func BenchmarkStdDecoder_DecodeStruct(b *testing.B) {
+	buf := new(bytes.Buffer)
+	records := make([]benchmarkRecord, 100)
+	for i := range records {
+		records[i] = benchmarkRecord{Id: i, Name: fmt.Sprintf("record %d", i), Price: 1.5, Enabled: true}
+	}
+	_, _ = io.WriteString(buf, "<methodResponse>")
+	if err := (&StdEncoder{}).encodeListArgs(buf, Args{records}); err != nil {
+		b.Fatal(err)
+	}
+	_, _ = io.WriteString(buf, "</methodResponse>")
+	response, err := NewResponse(buf.Bytes())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, cached := range []bool{false, true} {
+		b.Run(fmt.Sprintf("cached=%t", cached), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if !cached {
+					clearStructPlans()
+				}
+				var v []benchmarkRecord
+				if err := (&StdDecoder{}).Decode(response, &v); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkStructFieldLookup compares resolution of member names to fields by findFieldByNameOrTag, done for every member
+// before plans were cached, with cached plans.
+func BenchmarkStructFieldLookup(b *testing.B) {
+	v := reflect.ValueOf(&benchmarkRecord{}).Elem()
+	members := []string{"id", "name", "description", "price", "enabled", "owner", "CreatedAt", "updated_at"}
+
+	b.Run("findFieldByNameOrTag", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, m := range members {
+				if !findFieldByNameOrTag(v, structMemberToFieldName(m)).IsValid() {
+					b.Fatal(m)
+				}
+			}
+		}
+	})
+
+	b.Run("structPlan", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			plan := planOf(v.Type())
+			for _, m := range members {
+				if !plan.field(v, m).IsValid() {
+					b.Fatal(m)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,421 @@
+package xmlrpc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind represents the XML-RPC data type of a Value.
+type Kind int
+
+const (
+	// KindInvalid is the Kind of zero Value, e.g. when looking up a missing member or index.
+	KindInvalid Kind = iota
+	// KindInt represents <int> and <i4> values.
+	KindInt
+	// KindDouble represents <double> values.
+	KindDouble
+	// KindBoolean represents <boolean> values.
+	KindBoolean
+	// KindString represents <string> values.
+	KindString
+	// KindUntyped represents values without a type element, which are treated as strings.
+	KindUntyped
+	// KindBase64 represents <base64> values.
+	KindBase64
+	// KindDateTime represents <dateTime.iso8601> values.
+	KindDateTime
+	// KindArray represents <array> values.
+	KindArray
+	// KindStruct represents <struct> values.
+	KindStruct
+	// KindNil represents <nil/> values.
+	KindNil
+)
+
+var kindNames = map[Kind]string{
+	KindInvalid:  "invalid",
+	KindInt:      "int",
+	KindDouble:   "double",
+	KindBoolean:  "boolean",
+	KindString:   "string",
+	KindUntyped:  "untyped",
+	KindBase64:   "base64",
+	KindDateTime: "dateTime.iso8601",
+	KindArray:    "array",
+	KindStruct:   "struct",
+	KindNil:      "nil",
+}
+
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+
+	return "kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+var valueType = reflect.TypeOf(Value{})
+
+// Value is a dynamic representation of a decoded XML-RPC value that preserves its original data type.
+// It may be used as a decoding target (or element type of slices and maps) when response shape is not known up front.
+//
+// Accessors return an error if the Value is not of the expected Kind.
+// Index and Member return an invalid Value (see IsValid) if lookup fails, allowing calls to be chained.
+type Value struct {
+	kind    Kind
+	text    string
+	items   []Value
+	members []ValueMember
+}
+
+// ValueMember is a single named member of a <struct> Value.
+type ValueMember struct {
+	Name  string
+	Value Value
+}
+
+// newValue builds a Value tree out of the parsed response value.
+func newValue(rv *ResponseValue) Value {
+	switch {
+	case rv.Nil != nil:
+		return Value{kind: KindNil}
+	case rv.Int != nil:
+		return Value{kind: KindInt, text: *rv.Int}
+	case rv.Int4 != nil:
+		return Value{kind: KindInt, text: *rv.Int4}
+	case rv.Double != nil:
+		return Value{kind: KindDouble, text: *rv.Double}
+	case rv.Boolean != nil:
+		return Value{kind: KindBoolean, text: *rv.Boolean}
+	case rv.String != nil:
+		return Value{kind: KindString, text: *rv.String}
+	case rv.Base64 != nil:
+		return Value{kind: KindBase64, text: *rv.Base64}
+	case rv.DateTime != nil:
+		return Value{kind: KindDateTime, text: *rv.DateTime}
+	case rv.Array != nil:
+		items := make([]Value, len(rv.Array.Values))
+		for i, item := range rv.Array.Values {
+			items[i] = newValue(item)
+		}
+		return Value{kind: KindArray, items: items}
+	case len(rv.Struct) != 0 || isEmptyStruct(rv):
+		members := make([]ValueMember, len(rv.Struct))
+		for i, m := range rv.Struct {
+			members[i] = ValueMember{Name: m.Name, Value: newValue(&m.Value)}
+		}
+		return Value{kind: KindStruct, members: members}
+	default:
+		return Value{kind: KindUntyped, text: rv.RawXML}
+	}
+}
+
+// responseValue converts the Value back into a response value, so it can be processed by StdDecoder.
+func (v Value) responseValue() *ResponseValue {
+	text := v.text
+	rv := &ResponseValue{}
+
+	switch v.kind {
+	case KindInt:
+		rv.Int = &text
+	case KindDouble:
+		rv.Double = &text
+	case KindBoolean:
+		rv.Boolean = &text
+	case KindString:
+		rv.String = &text
+	case KindBase64:
+		rv.Base64 = &text
+	case KindDateTime:
+		rv.DateTime = &text
+	case KindArray:
+		rv.Array = &ResponseArrayData{Values: make([]*ResponseValue, len(v.items))}
+		for i, item := range v.items {
+			rv.Array.Values[i] = item.responseValue()
+		}
+	case KindStruct:
+		rv.Struct = make([]*ResponseStructMember, len(v.members))
+		for i, m := range v.members {
+			rv.Struct[i] = &ResponseStructMember{Name: m.Name, Value: *m.Value.responseValue()}
+		}
+		// Empty structs are told apart from untyped values by the raw XML
+		if len(v.members) == 0 {
+			rv.RawXML = "<struct></struct>"
+		}
+	case KindNil:
+		rv.Nil = &struct{}{}
+	default:
+		rv.RawXML = text
+	}
+
+	return rv
+}
+
+// Kind returns the XML-RPC data type of the Value.
+func (v Value) Kind() Kind {
+	return v.kind
+}
+
+// IsValid reports whether the Value holds any data. It returns false for the zero Value and failed lookups.
+func (v Value) IsValid() bool {
+	return v.kind != KindInvalid
+}
+
+// Int returns the value of <int> or <i4>.
+func (v Value) Int() (int, error) {
+	if err := v.mustBe(KindInt); err != nil {
+		return 0, err
+	}
+
+	return (&StdDecoder{}).decodeInt(v.text)
+}
+
+// Double returns the value of <double>.
+func (v Value) Double() (float64, error) {
+	if err := v.mustBe(KindDouble); err != nil {
+		return 0, err
+	}
+
+	return (&StdDecoder{}).decodeDouble(v.text)
+}
+
+// Bool returns the value of <boolean>.
+func (v Value) Bool() (bool, error) {
+	if err := v.mustBe(KindBoolean); err != nil {
+		return false, err
+	}
+
+	return (&StdDecoder{}).decodeBoolean(v.text)
+}
+
+// Text returns the value of <string> or untyped value.
+func (v Value) Text() (string, error) {
+	if err := v.mustBe(KindString, KindUntyped); err != nil {
+		return "", err
+	}
+
+	return v.text, nil
+}
+
+// Bytes returns decoded value of <base64>.
+func (v Value) Bytes() ([]byte, error) {
+	if err := v.mustBe(KindBase64); err != nil {
+		return nil, err
+	}
+
+	return (&StdDecoder{}).decodeBase64(v.text)
+}
+
+// Time returns the value of <dateTime.iso8601>.
+func (v Value) Time() (time.Time, error) {
+	if err := v.mustBe(KindDateTime); err != nil {
+		return time.Time{}, err
+	}
+
+	return (&StdDecoder{}).decodeDateTime(v.text)
+}
+
+// Array returns elements of an <array>.
+func (v Value) Array() ([]Value, error) {
+	if err := v.mustBe(KindArray); err != nil {
+		return nil, err
+	}
+
+	return v.items, nil
+}
+
+// Struct returns members of a <struct> by their name.
+// If member order matters, use Members instead.
+func (v Value) Struct() (map[string]Value, error) {
+	if err := v.mustBe(KindStruct); err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]Value, len(v.members))
+	for _, member := range v.members {
+		m[member.Name] = member.Value
+	}
+
+	return m, nil
+}
+
+// Members returns members of a <struct> in the order they were received.
+// For any other Kind, nil is returned.
+func (v Value) Members() []ValueMember {
+	return v.members
+}
+
+// Len returns the number of elements of an <array> or members of a <struct>.
+// For any other Kind, 0 is returned.
+func (v Value) Len() int {
+	switch v.kind {
+	case KindArray:
+		return len(v.items)
+	case KindStruct:
+		return len(v.members)
+	default:
+		return 0
+	}
+}
+
+// Index returns an element of an <array> at position i.
+// If Value is not an <array> or i is out of range, an invalid Value is returned.
+func (v Value) Index(i int) Value {
+	if v.kind != KindArray || i < 0 || i >= len(v.items) {
+		return Value{}
+	}
+
+	return v.items[i]
+}
+
+// Member returns a member of a <struct> with provided name.
+// If Value is not a <struct> or such member does not exist, an invalid Value is returned.
+func (v Value) Member(name string) Value {
+	if v.kind != KindStruct {
+		return Value{}
+	}
+
+	for _, m := range v.members {
+		if m.Name == name {
+			return m.Value
+		}
+	}
+
+	return Value{}
+}
+
+// Path looks up a nested Value by a dot-separated path, such as "bugs.0.id".
+// Numeric segments index into arrays, while all other segments are used as struct member names.
+// Dots that are part of a member name may be escaped with a backslash, e.g. "version\.major".
+func (v Value) Path(path string) (Value, error) {
+	current := v
+	if path == "" {
+		return current, nil
+	}
+
+	for _, segment := range splitPath(path) {
+		var next Value
+
+		switch current.kind {
+		case KindArray:
+			i, err := strconv.Atoi(segment)
+			if err != nil {
+				return Value{}, fmt.Errorf("invalid array index '%s' in path '%s'", segment, path)
+			}
+			next = current.Index(i)
+		case KindStruct:
+			next = current.Member(segment)
+		default:
+			return Value{}, fmt.Errorf("cannot look up '%s' in path '%s': value of kind %s is not an array or struct", segment, path, current.kind)
+		}
+
+		if !next.IsValid() {
+			return Value{}, fmt.Errorf("'%s' not found in path '%s'", segment, path)
+		}
+		current = next
+	}
+
+	return current, nil
+}
+
+// Interface returns the Value converted into native types, same as decoding into `any` would.
+func (v Value) Interface() (any, error) {
+	var out any
+	if err := v.Decode(&out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Decode decodes the Value into provided pointer, following the same rules as StdDecoder does for response params.
+// Decoding uses default options of StdDecoder, use DecodeWith to decode with options of a decoder (e.g. its dialect).
+func (v Value) Decode(into any) error {
+	return v.DecodeWith(&StdDecoder{}, into)
+}
+
+// DecodeWith decodes the Value into provided pointer as Decode does, using options of the decoder.
+func (v Value) DecodeWith(d *StdDecoder, into any) error {
+	if !v.IsValid() {
+		return errors.New("cannot decode invalid value")
+	}
+
+	rv := reflect.ValueOf(into)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer, got %T", into)
+	}
+
+	return d.decodeValue(v.responseValue(), rv)
+}
+
+// String returns a human-readable representation of the Value.
+func (v Value) String() string {
+	switch v.kind {
+	case KindInvalid:
+		return "<invalid>"
+	case KindNil:
+		return "nil"
+	case KindString, KindUntyped:
+		return strconv.Quote(v.text)
+	case KindArray:
+		items := make([]string, len(v.items))
+		for i, item := range v.items {
+			items[i] = item.String()
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case KindStruct:
+		members := make([]string, len(v.members))
+		for i, m := range v.members {
+			members[i] = strconv.Quote(m.Name) + ": " + m.Value.String()
+		}
+		return "{" + strings.Join(members, ", ") + "}"
+	default:
+		return v.text
+	}
+}
+
+func (v Value) mustBe(kinds ...Kind) error {
+	for _, k := range kinds {
+		if v.kind == k {
+			return nil
+		}
+	}
+
+	return &KindError{Expected: kinds[0], Actual: v.kind}
+}
+
+// KindError is returned by Value accessors when the Value is of a different Kind than requested.
+type KindError struct {
+	Expected Kind
+	Actual   Kind
+}
+
+func (e *KindError) Error() string {
+	return fmt.Sprintf("invalid value kind: expected '%s', got '%s'", e.Expected, e.Actual)
+}
+
+// splitPath splits a dot-separated path into segments, honoring backslash-escaped dots.
+func splitPath(path string) []string {
+	var segments []string
+	b := new(strings.Builder)
+
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == '\\' && i+1 < len(path) && path[i+1] == '.':
+			b.WriteByte('.')
+			i++
+		case path[i] == '.':
+			segments = append(segments, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(path[i])
+		}
+	}
+
+	return append(segments, b.String())
+}
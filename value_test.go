@@ -0,0 +1,244 @@
+package xmlrpc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decodeTestValue(t *testing.T, testFile string) Value {
+	v := &struct {
+		Value Value
+	}{}
+
+	dec := &StdDecoder{}
+	require.NoError(t, dec.DecodeRaw(loadTestFile(t, testFile), v))
+
+	return v.Value
+}
+
+func TestValue_Kinds(t *testing.T) {
+	v := decodeTestValue(t, "response_bugs.xml")
+	require.Equal(t, KindStruct, v.Kind())
+	require.Equal(t, 2, v.Len())
+
+	bug := v.Member("bugs").Index(0)
+	require.Equal(t, KindStruct, bug.Kind())
+
+	id, err := bug.Member("id").Int()
+	require.NoError(t, err)
+	require.Equal(t, 35, id)
+
+	summary, err := bug.Member("summary").Text()
+	require.NoError(t, err)
+	require.Equal(t, "Crash on startup", summary)
+
+	isOpen, err := bug.Member("is_open").Bool()
+	require.NoError(t, err)
+	require.True(t, isOpen)
+
+	score, err := bug.Member("score").Double()
+	require.NoError(t, err)
+	require.Equal(t, 4.5, score)
+
+	changed, err := bug.Member("last_change_time").Time()
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), changed)
+
+	status := bug.Member("status")
+	require.Equal(t, KindUntyped, status.Kind())
+	statusText, err := status.Text()
+	require.NoError(t, err)
+	require.Equal(t, "NEW", statusText)
+
+	// Kind is preserved, even though numeric value would fit an int
+	require.Equal(t, KindDouble, v.Member("bugs").Index(1).Member("score").Kind())
+
+	faults, err := v.Member("faults").Array()
+	require.NoError(t, err)
+	require.Empty(t, faults)
+}
+
+func TestValue_KindError(t *testing.T) {
+	v := decodeTestValue(t, "response_bugs.xml")
+
+	_, err := v.Member("bugs").Index(0).Member("summary").Int()
+	require.Error(t, err)
+
+	kErr := &KindError{}
+	require.True(t, errors.As(err, &kErr))
+	require.Equal(t, KindInt, kErr.Expected)
+	require.Equal(t, KindString, kErr.Actual)
+	require.EqualError(t, err, "invalid value kind: expected 'int', got 'string'")
+}
+
+func TestValue_Lookups(t *testing.T) {
+	v := decodeTestValue(t, "response_bugs.xml")
+
+	require.False(t, v.Member("missing").IsValid())
+	require.False(t, v.Member("bugs").Index(5).IsValid())
+	require.False(t, v.Member("missing").Index(0).Member("id").IsValid())
+	require.False(t, v.Index(0).IsValid())
+
+	members := v.Members()
+	require.Len(t, members, 2)
+	require.Equal(t, "bugs", members[0].Name)
+	require.Equal(t, "faults", members[1].Name)
+
+	m, err := v.Struct()
+	require.NoError(t, err)
+	require.Contains(t, m, "bugs")
+	require.Contains(t, m, "faults")
+}
+
+func TestValue_Path(t *testing.T) {
+	v := decodeTestValue(t, "response_bugs.xml")
+
+	tests := map[string]struct {
+		path   string
+		expect string
+		err    string
+	}{
+		"empty path": {
+			path:   "",
+			expect: v.String(),
+		},
+		"nested member": {
+			path:   "bugs.1.id",
+			expect: "36",
+		},
+		"nested string": {
+			path:   "bugs.0.summary",
+			expect: `"Crash on startup"`,
+		},
+		"missing member": {
+			path: "bugs.0.assignee",
+			err:  "'assignee' not found in path 'bugs.0.assignee'",
+		},
+		"index out of range": {
+			path: "bugs.2.id",
+			err:  "'2' not found in path 'bugs.2.id'",
+		},
+		"invalid index": {
+			path: "bugs.first.id",
+			err:  "invalid array index 'first' in path 'bugs.first.id'",
+		},
+		"lookup in scalar": {
+			path: "bugs.0.id.value",
+			err:  "cannot look up 'value' in path 'bugs.0.id.value': value of kind int is not an array or struct",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, err := v.Path(tt.path)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, r.String())
+		})
+	}
+}
+
+func TestValue_Decode(t *testing.T) {
+	v := decodeTestValue(t, "response_bugs.xml")
+
+	type Bug struct {
+		Id             int
+		Summary        string
+		IsOpen         bool
+		Score          float64
+		LastChangeTime time.Time
+		Status         string
+	}
+
+	bugs := []Bug{}
+	require.NoError(t, v.Member("bugs").Decode(&bugs))
+	require.Equal(t, []Bug{
+		{Id: 35, Summary: "Crash on startup", IsOpen: true, Score: 4.5, LastChangeTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Status: "NEW"},
+		{Id: 36, Summary: "Typo in docs", IsOpen: false, Score: 1, LastChangeTime: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC), Status: "RESOLVED"},
+	}, bugs)
+
+	id, err := v.Path("bugs.0.id")
+	require.NoError(t, err)
+	i, err := id.Interface()
+	require.NoError(t, err)
+	require.Equal(t, 35, i)
+
+	require.Error(t, v.Decode(bugs))
+	require.Error(t, v.Member("missing").Decode(&bugs))
+}
+
+func TestValue_EmptyStruct(t *testing.T) {
+	for _, raw := range []string{`<struct></struct>`, `<struct/>`, "<struct>\n</struct>"} {
+		v := Value{}
+		require.NoError(t, RawValue(raw).Decode(&v), raw)
+		require.Equal(t, KindStruct, v.Kind(), raw)
+
+		members, err := v.Struct()
+		require.NoError(t, err, raw)
+		require.Empty(t, members, raw)
+		require.Empty(t, v.Members(), raw)
+		require.Equal(t, "{}", v.String(), raw)
+
+		// Encoded back as an empty struct
+		buf := new(strings.Builder)
+		require.NoError(t, (&StdEncoder{}).encodeValue(buf, v))
+		require.Equal(t, "<value><struct></struct></value>", buf.String())
+	}
+}
+
+func TestValue_DecodeWith(t *testing.T) {
+	v := Value{}
+	require.NoError(t, RawValue(`<dateTime.iso8601>20240517T09:30:00</dateTime.iso8601>`).Decode(&v))
+
+	// Default options do not accept times in the layout of the dialect
+	var when time.Time
+	require.Error(t, v.Decode(&when))
+	require.NoError(t, v.DecodeWith(&StdDecoder{dialect: DialectPython}, &when))
+	require.Equal(t, time.Date(2024, 5, 17, 9, 30, 0, 0, time.UTC), when)
+}
+
+func TestValue_String(t *testing.T) {
+	v := decodeTestValue(t, "response_array_mixed.xml")
+	require.Equal(t, `[10, "s11", 1]`, v.String())
+	require.Equal(t, "<invalid>", Value{}.String())
+}
+
+func TestValue_Nil(t *testing.T) {
+	v := decodeTestValue(t, "response_nil.xml")
+
+	realName := v.Member("real_name")
+	require.Equal(t, KindNil, realName.Kind())
+	require.Equal(t, "nil", realName.String())
+
+	i, err := realName.Interface()
+	require.NoError(t, err)
+	require.Nil(t, i)
+}
+
+func TestValue_Encode(t *testing.T) {
+	for _, testFile := range []string{"response_bugs.xml", "response_nil.xml", "response_array_mixed_missing_types.xml", "response_polymorphic.xml"} {
+		t.Run(testFile, func(t *testing.T) {
+			v := decodeTestValue(t, testFile)
+
+			buf := new(strings.Builder)
+			require.NoError(t, (&StdEncoder{}).encodeResponse(buf, v, true))
+
+			// Re-decoding the encoded value results in the same value
+			decoded := &struct {
+				Value Value
+			}{}
+			require.NoError(t, (&StdDecoder{}).DecodeRaw([]byte(buf.String()), decoded))
+			require.Equal(t, v, decoded.Value)
+		})
+	}
+
+	require.EqualError(t, (&StdEncoder{}).encodeValue(new(strings.Builder), Value{}), "cannot encode invalid value")
+}
@@ -0,0 +1,65 @@
+// Package xmlrpctest provides testify-style assertions for comparing XML-RPC documents in tests.
+//
+//	xmlrpctest.Equal(t, expectedBody, actualBody)
+//	xmlrpctest.RequireEqual(t, expectedBody, actualBody, xmlrpc.TimeTolerance(time.Second))
+//
+// Documents are compared semantically with xmlrpc.Diff, so formatting and order of struct members do not matter.
+package xmlrpctest
+
+import (
+	"strings"
+
+	"alexejk.io/go-xmlrpc"
+)
+
+// TestingT is the subset of testing.TB used by assertions.
+type TestingT interface {
+	Errorf(format string, args ...any)
+	FailNow()
+}
+
+// Document is an XML-RPC document passed to assertions.
+type Document interface {
+	~string | ~[]byte
+}
+
+type tHelper interface {
+	Helper()
+}
+
+// Equal asserts that two <methodCall> or <methodResponse> documents are semantically equal,
+// reporting paths of all differences otherwise. It returns whether the assertion succeeded.
+func Equal[E, A Document](t TestingT, expected E, actual A, opts ...xmlrpc.CompareOption) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	diff, err := xmlrpc.Diff([]byte(expected), []byte(actual), opts...)
+	if err != nil {
+		t.Errorf("Cannot compare XML-RPC documents: %v", err)
+		return false
+	}
+
+	if len(diff) == 0 {
+		return true
+	}
+
+	lines := make([]string, len(diff))
+	for i, d := range diff {
+		lines[i] = "\t" + d.String()
+	}
+	t.Errorf("XML-RPC documents are not equal (expected != actual):\n%s", strings.Join(lines, "\n"))
+
+	return false
+}
+
+// RequireEqual is the same as Equal, but stops the test with FailNow if documents are not equal.
+func RequireEqual[E, A Document](t TestingT, expected E, actual A, opts ...xmlrpc.CompareOption) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	if !Equal(t, expected, actual, opts...) {
+		t.FailNow()
+	}
+}
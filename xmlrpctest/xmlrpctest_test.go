@@ -0,0 +1,66 @@
+package xmlrpctest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"alexejk.io/go-xmlrpc"
+)
+
+// mockT records failures of assertions.
+type mockT struct {
+	errors []string
+	failed bool
+}
+
+func (m *mockT) Errorf(format string, args ...any) {
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
+
+func (m *mockT) FailNow() {
+	m.failed = true
+}
+
+func TestEqual(t *testing.T) {
+	expected := `<methodResponse><params><param><value><struct>
+		<member><name>id</name><value><int>35</int></value></member>
+		<member><name>when</name><value><dateTime.iso8601>20240102T03:04:05</dateTime.iso8601></value></member>
+	</struct></value></param></params></methodResponse>`
+
+	m := &mockT{}
+	require.True(t, Equal(m, expected, []byte(`<methodResponse><params><param><value><struct><member><name>when</name><value><dateTime.iso8601>2024-01-02T03:04:05Z</dateTime.iso8601></value></member><member><name>id</name><value><i4>35</i4></value></member></struct></value></param></params></methodResponse>`)))
+	require.Empty(t, m.errors)
+
+	m = &mockT{}
+	actual := `<methodResponse><params><param><value><struct><member><name>id</name><value><int>36</int></value></member><member><name>when</name><value><dateTime.iso8601>20240102T03:04:06</dateTime.iso8601></value></member></struct></value></param></params></methodResponse>`
+	require.False(t, Equal(m, expected, actual))
+	require.Equal(t, []string{"XML-RPC documents are not equal (expected != actual):\n" +
+		"\tparams.0.id: 35 (int) != 36 (int)\n" +
+		"\tparams.0.when: 20240102T03:04:05 (dateTime.iso8601) != 20240102T03:04:06 (dateTime.iso8601)"}, m.errors)
+	require.False(t, m.failed)
+
+	m = &mockT{}
+	require.False(t, Equal(m, expected, actual, xmlrpc.TimeTolerance(time.Second)))
+	require.Len(t, m.errors, 1)
+	require.NotContains(t, m.errors[0], "params.0.when")
+
+	m = &mockT{}
+	require.False(t, Equal(m, expected, "<methodResponse>"))
+	require.Equal(t, []string{"Cannot compare XML-RPC documents: cannot parse second document: XML syntax error on line 1: unexpected EOF"}, m.errors)
+}
+
+func TestRequireEqual(t *testing.T) {
+	m := &mockT{}
+	RequireEqual(m, "<methodCall><methodName>a</methodName></methodCall>", "<methodCall><methodName>a</methodName></methodCall>")
+	require.False(t, m.failed)
+
+	RequireEqual(m, "<methodCall><methodName>a</methodName></methodCall>", "<methodCall><methodName>b</methodName></methodCall>")
+	require.True(t, m.failed)
+	require.Equal(t, []string{"XML-RPC documents are not equal (expected != actual):\n\tmethodName: \"a\" != \"b\""}, m.errors)
+}
+
+// TestingT is satisfied by testing.TB.
+var _ TestingT = (*testing.T)(nil)